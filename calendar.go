@@ -0,0 +1,352 @@
+package dcron
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar excludes planned runs of jobs, e.g. on holidays or during maintenance windows,
+// see WithCalendar and WithJobCalendar. The excluded tasks are skipped with the reason in Task.SkipReason.
+type Calendar interface {
+	// Excluded returns true and the reason if no task should run at t.
+	Excluded(t time.Time) (bool, string)
+}
+
+// CalendarFunc is a Calendar of a function.
+type CalendarFunc func(t time.Time) (bool, string)
+
+// Excluded implements Calendar.Excluded.
+func (f CalendarFunc) Excluded(t time.Time) (bool, string) {
+	return f(t)
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// NewDateCalendar returns a Calendar excluding whole days of the dates,
+// the days are compared in the time zone of the planned run.
+func NewDateCalendar(reason string, dates ...time.Time) Calendar {
+	days := map[date]bool{}
+	for _, d := range dates {
+		y, m, day := d.Date()
+		days[date{y, m, day}] = true
+	}
+	return CalendarFunc(func(t time.Time) (bool, string) {
+		y, m, d := t.Date()
+		if days[date{y, m, d}] {
+			return true, reason
+		}
+		return false, ""
+	})
+}
+
+// NewWeekdayCalendar returns a Calendar excluding the days of week, e.g. weekends.
+func NewWeekdayCalendar(reason string, weekdays ...time.Weekday) Calendar {
+	return CalendarFunc(func(t time.Time) (bool, string) {
+		for _, weekday := range weekdays {
+			if t.Weekday() == weekday {
+				return true, reason
+			}
+		}
+		return false, ""
+	})
+}
+
+// NewPeriodCalendar returns a Calendar excluding the times from start inclusive to end exclusive,
+// e.g. a maintenance window.
+func NewPeriodCalendar(reason string, start, end time.Time) Calendar {
+	return CalendarFunc(func(t time.Time) (bool, string) {
+		if !t.Before(start) && t.Before(end) {
+			return true, reason
+		}
+		return false, ""
+	})
+}
+
+// Calendars returns a Calendar excluding the times excluded by any of the calendars,
+// the reason is the one of the first calendar excluding the time.
+func Calendars(calendars ...Calendar) Calendar {
+	return CalendarFunc(func(t time.Time) (bool, string) {
+		for _, calendar := range calendars {
+			if excluded, reason := calendar.Excluded(t); excluded {
+				return true, reason
+			}
+		}
+		return false, ""
+	})
+}
+
+// ParseICal returns a Calendar excluding the events of an iCalendar (RFC 5545), such as a holiday calendar export.
+// An event excludes the times from its DTSTART to its DTEND, or the whole day of an all-day event without DTEND,
+// and its SUMMARY is the reason.
+// A recurring event is expanded if its RRULE is FREQ=YEARLY or FREQ=DAILY with optional INTERVAL, COUNT and UNTIL,
+// e.g. an annual holiday. Events with other rules or with EXDATE or RDATE are skipped.
+func ParseICal(r io.Reader) (Calendar, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []Calendar
+	var event map[string]icalProperty
+	for i, line := range lines {
+		name, property, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case name == "BEGIN" && property.value == "VEVENT":
+			event = map[string]icalProperty{}
+		case name == "END" && property.value == "VEVENT":
+			if event == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", i+1)
+			}
+			calendar, err := icalEvent(event)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if calendar != nil {
+				calendars = append(calendars, calendar)
+			}
+			event = nil
+		case event != nil:
+			event[name] = property
+		}
+	}
+	if event != nil {
+		return nil, errors.New("BEGIN:VEVENT without END:VEVENT")
+	}
+	return Calendars(calendars...), nil
+}
+
+type icalProperty struct {
+	params map[string]string
+	value  string
+}
+
+// unfoldICal joins the lines folded by a leading space or tab.
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseICalLine parses NAME;PARAM=VALUE:VALUE, params with quoted values containing ; or : are not supported.
+func parseICalLine(line string) (string, icalProperty, error) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", icalProperty{}, fmt.Errorf("no value in %q", line)
+	}
+	parts := strings.Split(line[:i], ";")
+	property := icalProperty{params: map[string]string{}, value: line[i+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), property, nil
+}
+
+// icalEvent returns the Calendar of the event, or nil if its recurrence is not supported.
+func icalEvent(event map[string]icalProperty) (Calendar, error) {
+	dtstart, ok := event["DTSTART"]
+	if !ok {
+		return nil, errors.New("event without DTSTART")
+	}
+	start, allDay, err := parseICalTime(dtstart)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %w", err)
+	}
+	reason := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(event["SUMMARY"].value)
+
+	var rule *icalRule
+	if rrule, ok := event["RRULE"]; ok {
+		if _, ok := event["EXDATE"]; ok {
+			return nil, nil
+		}
+		if _, ok := event["RDATE"]; ok {
+			return nil, nil
+		}
+		if rule, err = parseICalRule(rrule.value); err != nil {
+			return nil, fmt.Errorf("RRULE: %w", err)
+		}
+		if rule == nil {
+			return nil, nil
+		}
+	}
+
+	dtend, ok := event["DTEND"]
+	if !ok {
+		if !allDay {
+			// an event of a moment excludes nothing
+			return CalendarFunc(func(t time.Time) (bool, string) { return false, "" }), nil
+		}
+		if rule != nil {
+			return rule.calendar(reason, start, start.AddDate(0, 0, 1), true), nil
+		}
+		return NewDateCalendar(reason, start), nil
+	}
+	end, endAllDay, err := parseICalTime(dtend)
+	if err != nil {
+		return nil, fmt.Errorf("DTEND: %w", err)
+	}
+	if rule != nil {
+		return rule.calendar(reason, start, end, allDay && endAllDay), nil
+	}
+	if allDay && endAllDay {
+		// all-day events are excluded by the dates, so they follow the time zone of the planned run
+		var dates []time.Time
+		for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+			dates = append(dates, d)
+		}
+		return NewDateCalendar(reason, dates...), nil
+	}
+	return NewPeriodCalendar(reason, start, end), nil
+}
+
+// icalRule is a FREQ=YEARLY or FREQ=DAILY recurrence rule.
+type icalRule struct {
+	yearly   bool
+	interval int
+	count    int       // the number of occurrences, 0 is unlimited
+	until    time.Time // the last start of an occurrence, zero is unlimited
+}
+
+// parseICalRule parses the RRULE value, it returns nil if the rule is not supported.
+func parseICalRule(value string) (*icalRule, error) {
+	rule := &icalRule{interval: 1}
+	var freq string
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			freq = strings.ToUpper(v)
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(v)
+			if err == nil && rule.interval < 1 {
+				err = fmt.Errorf("bad interval %v", rule.interval)
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(v)
+			if err == nil && rule.count < 1 {
+				err = fmt.Errorf("bad count %v", rule.count)
+			}
+		case "UNTIL":
+			rule.until, _, err = parseICalTime(icalProperty{value: v})
+		case "WKST":
+			// the start of the week does not matter for yearly and daily rules
+		default:
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", k, err)
+		}
+	}
+	switch freq {
+	case "YEARLY":
+		rule.yearly = true
+	case "DAILY":
+	default:
+		return nil, nil
+	}
+	return rule, nil
+}
+
+// calendar returns a Calendar excluding the occurrences of the event from start to end.
+// The occurrences of an all-day event are excluded by the dates, as NewDateCalendar does.
+func (r *icalRule) calendar(reason string, start, end time.Time, allDay bool) Calendar {
+	days := int(end.Sub(start).Hours() / 24)
+	duration := end.Sub(start)
+	return CalendarFunc(func(t time.Time) (bool, string) {
+		if allDay {
+			y, m, d := t.Date()
+			t = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		}
+		if t.Before(start) {
+			return false, ""
+		}
+
+		// the latest occurrence started before t is about the k-th one
+		var k int
+		if r.yearly {
+			k = (t.Year() - start.Year()) / r.interval
+		} else {
+			k = int(t.Sub(start).Hours()/24) / r.interval
+		}
+		for i := k + 1; i >= k-1 && i >= 0; i-- {
+			occurrence, ok := r.occurrence(start, i)
+			if !ok || occurrence.After(t) {
+				continue
+			}
+			occurrenceEnd := occurrence.Add(duration)
+			if allDay {
+				occurrenceEnd = occurrence.AddDate(0, 0, days)
+			}
+			if t.Before(occurrenceEnd) {
+				return true, reason
+			}
+		}
+		return false, ""
+	})
+}
+
+// occurrence returns the start of the i-th occurrence, or false if it does not exist.
+func (r *icalRule) occurrence(start time.Time, i int) (time.Time, bool) {
+	if r.count > 0 && i >= r.count {
+		return time.Time{}, false
+	}
+	var occurrence time.Time
+	if r.yearly {
+		occurrence = start.AddDate(i*r.interval, 0, 0)
+		if occurrence.Day() != start.Day() {
+			// February 29 occurs in leap years only
+			return time.Time{}, false
+		}
+	} else {
+		occurrence = start.AddDate(0, 0, i*r.interval)
+	}
+	if !r.until.IsZero() && occurrence.After(r.until) {
+		return time.Time{}, false
+	}
+	return occurrence, true
+}
+
+// parseICalTime parses a DATE or a DATE-TIME in UTC, in the TZID time zone or a floating one in the local time zone.
+func parseICalTime(property icalProperty) (time.Time, bool, error) {
+	if property.params["VALUE"] == "DATE" || len(property.value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", property.value, time.UTC)
+		return t, true, err
+	}
+	if strings.HasSuffix(property.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", property.value)
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid, ok := property.params["TZID"]; ok {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", property.value, loc)
+	return t, false, err
+}
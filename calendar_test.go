@@ -0,0 +1,209 @@
+package dcron
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCalendars(t *testing.T) {
+	utc := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	calendar := Calendars(
+		NewDateCalendar("new year", utc("2024-01-01T00:00:00Z")),
+		NewWeekdayCalendar("weekend", time.Saturday, time.Sunday),
+		NewPeriodCalendar("maintenance", utc("2024-01-03T22:00:00Z"), utc("2024-01-04T02:00:00Z")),
+	)
+
+	tests := []struct {
+		t          string
+		wantReason string
+	}{
+		{t: "2024-01-01T09:00:00Z", wantReason: "new year"},
+		{t: "2024-01-01T09:00:00+03:00", wantReason: "new year"},
+		{t: "2024-01-02T01:00:00+03:00"},
+		{t: "2024-01-02T09:00:00Z"},
+		{t: "2024-01-03T21:59:59Z"},
+		{t: "2024-01-03T22:00:00Z", wantReason: "maintenance"},
+		{t: "2024-01-04T02:00:00Z"},
+		{t: "2024-01-06T09:00:00Z", wantReason: "weekend"},
+	}
+	for _, tt := range tests {
+		excluded, reason := calendar.Excluded(utc(tt.t))
+		if excluded != (tt.wantReason != "") || reason != tt.wantReason {
+			t.Errorf("Excluded(%v) = %v, %q, want %q", tt.t, excluded, reason, tt.wantReason)
+		}
+	}
+}
+
+func TestParseICal(t *testing.T) {
+	ical := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20241225",
+		"DTEND;VALUE=DATE:20241227",
+		"SUMMARY:Christmas\\, Boxing",
+		"  Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240101",
+		"SUMMARY:New Year",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Europe/Berlin:20240301T220000",
+		"DTEND:20240302T000000Z",
+		"SUMMARY:Maintenance",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	calendar, err := ParseICal(strings.NewReader(ical))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		t          time.Time
+		wantReason string
+	}{
+		{t: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), wantReason: "Christmas, Boxing Day"},
+		{t: time.Date(2024, 12, 26, 23, 0, 0, 0, time.UTC), wantReason: "Christmas, Boxing Day"},
+		{t: time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), wantReason: "New Year"},
+		{t: time.Date(2024, 3, 1, 20, 59, 0, 0, time.UTC)},
+		{t: time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC), wantReason: "Maintenance"},
+		{t: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		excluded, reason := calendar.Excluded(tt.t)
+		if excluded != (tt.wantReason != "") || reason != tt.wantReason {
+			t.Errorf("Excluded(%v) = %v, %q, want %q", tt.t, excluded, reason, tt.wantReason)
+		}
+	}
+}
+
+func TestParseICal_recurring(t *testing.T) {
+	ical := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20201225",
+		"DTEND;VALUE=DATE:20201227",
+		"RRULE:FREQ=YEARLY",
+		"SUMMARY:Christmas",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20200229",
+		"RRULE:FREQ=YEARLY;UNTIL=20280301",
+		"SUMMARY:Leap day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20240101T020000Z",
+		"DTEND:20240101T030000Z",
+		"RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3",
+		"SUMMARY:Backup",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240101",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"SUMMARY:Unsupported",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240102",
+		"RRULE:FREQ=DAILY",
+		"EXDATE;VALUE=DATE:20240103",
+		"SUMMARY:With exceptions",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	calendar, err := ParseICal(strings.NewReader(ical))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		t          time.Time
+		wantReason string
+	}{
+		{t: time.Date(2019, 12, 25, 12, 0, 0, 0, time.UTC)},
+		{t: time.Date(2020, 12, 25, 12, 0, 0, 0, time.UTC), wantReason: "Christmas"},
+		{t: time.Date(2031, 12, 26, 23, 0, 0, 0, time.UTC), wantReason: "Christmas"},
+		{t: time.Date(2031, 12, 27, 0, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), wantReason: "Leap day"},
+		{t: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{t: time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC), wantReason: "Leap day"},
+		{t: time.Date(2032, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 1, 2, 30, 0, 0, time.UTC), wantReason: "Backup"},
+		{t: time.Date(2024, 1, 2, 2, 30, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 5, 2, 0, 0, 0, time.UTC), wantReason: "Backup"},
+		{t: time.Date(2024, 1, 5, 3, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 7, 2, 30, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)},
+		{t: time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		excluded, reason := calendar.Excluded(tt.t)
+		if excluded != (tt.wantReason != "") || reason != tt.wantReason {
+			t.Errorf("Excluded(%v) = %v, %q, want %q", tt.t, excluded, reason, tt.wantReason)
+		}
+	}
+}
+
+func TestParseICal_error(t *testing.T) {
+	for _, ical := range []string{
+		"BEGIN:VEVENT\nDTSTART:20240101\n",
+		"BEGIN:VEVENT\nSUMMARY:no start\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20240101\nRRULE:FREQ=YEARLY;COUNT=x\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20240101\nRRULE:FREQ=DAILY;INTERVAL=0\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:2024-01-01\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20240101T000000\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nbroken line\nEND:VEVENT\n",
+	} {
+		if _, err := ParseICal(strings.NewReader(ical)); err == nil {
+			t.Errorf("ParseICal(%q) succeeded", ical)
+		}
+	}
+}
+
+func TestWithJobCalendar(t *testing.T) {
+	var mu sync.Mutex
+	var tasks []Task
+	c := NewCron(WithCalendar(NewWeekdayCalendar("never on a weekday", time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)))
+	job := NewJob("job", "* * * * * *", func(ctx context.Context) error {
+		t.Error("an excluded task ran")
+		return nil
+	}, WithJobCalendar(NewWeekdayCalendar("never on a weekend", time.Saturday, time.Sunday)),
+		WithAfterContextFunc(func(ctx context.Context, task Task) {
+			mu.Lock()
+			tasks = append(tasks, task)
+			mu.Unlock()
+		}))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+	time.Sleep(1500 * time.Millisecond)
+	<-c.Stop().Done()
+
+	if len(tasks) == 0 {
+		t.Fatal("the job has never been planned")
+	}
+	for _, task := range tasks {
+		if !task.Skipped || !strings.HasPrefix(task.SkipReason, "never on a") {
+			t.Errorf("task planned at %v: skipped %v, reason %q", task.PlanAt, task.Skipped, task.SkipReason)
+		}
+	}
+	if stats := c.Statistics(); stats.SkippedTask != int64(len(tasks)) {
+		t.Errorf("statistics %+v", stats)
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+// defaultParser parses the specs with the seconds field.
+var defaultParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
 // CronMeta is a read only wrapper for Cron.
 type CronMeta interface {
 	// Hostname returns current hostname.
@@ -34,9 +38,14 @@ type SlogLogger interface {
 // Cron keeps track of any number of jobs, invoking the associated func as specified.
 type Cron struct {
 	hostname      string
+	namespace     string
 	cron          *cron.Cron
+	parser        cron.ScheduleParser
+	calendar      Calendar
+	anchor        *time.Time
 	lock          Lock
 	jobs          []*innerJob
+	jobsMu        sync.RWMutex
 	location      *time.Location
 	context       context.Context
 	contextCancel context.CancelFunc
@@ -55,7 +64,7 @@ func NewCron(options ...CronOption) *Cron {
 	}
 
 	ret.cron = cron.New(
-		cron.WithSeconds(),
+		cron.WithParser(ret.scheduleParser()),
 		cron.WithLogger(cron.DiscardLogger),
 		cron.WithLocation(ret.location),
 	)
@@ -81,6 +90,9 @@ func (c *Cron) addJob(job Job) error {
 		return errors.New("empty key")
 	}
 
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+
 	for _, j := range c.jobs {
 		if j.key == job.Key() {
 			return errors.New("added already")
@@ -103,16 +115,186 @@ func (c *Cron) addJob(job Job) error {
 	if j.retryTimes < 1 {
 		j.retryTimes = 1
 	}
+	if err := j.window.validate(); err != nil {
+		return err
+	}
+	if err := c.validateFailover(j); err != nil {
+		return err
+	}
+	if validator, ok := c.lock.(LockValidator); ok && !j.noLock {
+		if err := validator.ValidateJob(j, j.settings); err != nil {
+			return err
+		}
+	}
 
-	entryID, err := c.cron.AddJob(j.Spec(), j)
+	schedule, err := c.schedule(job, j)
 	if err != nil {
 		return err
 	}
-	j.entryID = entryID
+	if err := j.validateJitter(schedule); err != nil {
+		return err
+	}
+	j.entryID = c.cron.Schedule(schedule, j)
 	c.jobs = append(c.jobs, j)
 	return nil
 }
 
+// removeJob removes the job from the scheduler and from the jobs of the cron.
+func (c *Cron) removeJob(job *innerJob) {
+	c.cron.Remove(job.entryID)
+
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+	for i, j := range c.jobs {
+		if j == job {
+			c.jobs = append(c.jobs[:i:i], c.jobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// schedule parses the specs of the job in the time zone of the job,
+// which is set by WithJobLocation, or by the CRON_TZ= prefix of the first spec having it, or is the one of the cron otherwise.
+// A ScheduleJob is its own schedule, and a job with several specs runs on the union of their schedules.
+func (c *Cron) schedule(job Job, j *innerJob) (cron.Schedule, error) {
+	jobLocation := j.location
+	var schedules []cron.Schedule
+	var anchored bool
+
+	if scheduleJob, ok := job.(ScheduleJob); ok {
+		if j.anchor != nil {
+			return nil, errors.New("both custom schedule and job interval anchor")
+		}
+		if jobLocation == nil {
+			schedules = append(schedules, scheduleJob)
+		} else {
+			schedules = append(schedules, locatedSchedule{schedule: scheduleJob, location: jobLocation})
+		}
+	} else {
+		schedule, location, err := c.parseSpec(j.spec, jobLocation, j.intervalAnchor())
+		if err != nil {
+			return nil, err
+		}
+		j.location = location
+		schedules = append(schedules, schedule)
+		_, anchored = schedule.(IntervalSchedule)
+	}
+
+	for _, spec := range j.specs {
+		schedule, location, err := c.parseSpec(spec, jobLocation, j.intervalAnchor())
+		if err != nil {
+			return nil, fmt.Errorf("spec %v: %w", spec, err)
+		}
+		if j.location == nil {
+			j.location = location
+		}
+		schedules = append(schedules, schedule)
+		if _, ok := schedule.(IntervalSchedule); ok {
+			anchored = true
+		}
+	}
+	if j.anchor != nil && !anchored {
+		return nil, errors.New("job interval anchor without an @every spec")
+	}
+
+	if j.location == nil {
+		j.location = c.location
+	}
+	if len(schedules) == 1 {
+		return schedules[0], nil
+	}
+	return unionSchedule(schedules), nil
+}
+
+// parseSpec parses the spec, overriding its time zone by the job location if it is set,
+// and anchoring its interval if the anchor is set.
+// It returns the time zone of the schedule, or nil if the schedule uses the one of the cron.
+func (c *Cron) parseSpec(spec string, jobLocation *time.Location, anchor *time.Time) (cron.Schedule, *time.Location, error) {
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		if jobLocation != nil {
+			return nil, nil, errors.New("both time zone prefix and job location")
+		}
+		if !strings.Contains(spec, " ") {
+			return nil, nil, errNoScheduleAfterTimeZone
+		}
+	}
+
+	schedule, err := c.scheduleParser().Parse(spec)
+	if err != nil {
+		if c.parser == nil {
+			return nil, nil, locateSpecError(spec, err)
+		}
+		return nil, nil, err
+	}
+	switch s := schedule.(type) {
+	case *cron.SpecSchedule:
+		return schedule, scheduleLocation(&s.Location, jobLocation), nil
+	case *quartzSchedule:
+		return schedule, scheduleLocation(&s.Location, jobLocation), nil
+	case cron.ConstantDelaySchedule:
+		return anchorInterval(schedule, anchor), jobLocation, nil
+	}
+	// a schedule of a custom parser is evaluated in the job location as a ScheduleJob is
+	if jobLocation != nil {
+		return locatedSchedule{schedule: schedule, location: jobLocation}, jobLocation, nil
+	}
+	return schedule, nil, nil
+}
+
+// unionSchedule runs at the runs of any of the schedules.
+type unionSchedule []cron.Schedule
+
+// Next implements cron.Schedule.Next.
+func (s unionSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range s {
+		if n := schedule.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// locatedSchedule passes the times in the location to the schedule.
+type locatedSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// Next implements cron.Schedule.Next.
+func (s locatedSchedule) Next(t time.Time) time.Time {
+	return s.schedule.Next(t.In(s.location))
+}
+
+// scheduleLocation overrides the location of a schedule by the job location if it is set,
+// and returns the location of the schedule otherwise, or nil if the schedule uses the one of the cron.
+func scheduleLocation(scheduleLocation **time.Location, jobLocation *time.Location) *time.Location {
+	if jobLocation != nil {
+		*scheduleLocation = jobLocation
+		return jobLocation
+	}
+	if *scheduleLocation != time.Local {
+		return *scheduleLocation
+	}
+	return nil
+}
+
+// scheduleParser returns the parser of the specs.
+func (c *Cron) scheduleParser() cron.ScheduleParser {
+	if c.parser == nil {
+		return defaultParser
+	}
+	return c.parser
+}
+
+// lockKey returns the key passed to the Lock for the job key.
+func (c *Cron) lockKey(key string) string {
+	if c.namespace == "" {
+		return key
+	}
+	return c.namespace + ":" + key
+}
+
 // Start the cron scheduler in its own goroutine, or no-op if already started.
 func (c *Cron) Start() {
 	if c.context != nil {
@@ -151,6 +333,9 @@ func (c *Cron) Hostname() string {
 
 // Statistics implements CronMeta.Statistics
 func (c *Cron) Statistics() Statistics {
+	c.jobsMu.RLock()
+	defer c.jobsMu.RUnlock()
+
 	ret := Statistics{}
 	for _, j := range c.jobs {
 		ret = ret.Add(j.statistics)
@@ -160,6 +345,9 @@ func (c *Cron) Statistics() Statistics {
 
 // Jobs implements CronMeta.Jobs
 func (c *Cron) Jobs() []JobMeta {
+	c.jobsMu.RLock()
+	defer c.jobsMu.RUnlock()
+
 	var ret []JobMeta
 	for _, j := range c.jobs {
 		ret = append(ret, j)
@@ -3,6 +3,8 @@ package dcron
 import (
 	"context"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 // CronOption represents a modification to the default behavior of a Cron.
@@ -15,6 +17,14 @@ func WithHostname(hostname string) CronOption {
 	}
 }
 
+// WithNamespace prefixes the keys passed to the Lock with "namespace:",
+// so services defining jobs with the same keys do not contend on the same locks.
+func WithNamespace(namespace string) CronOption {
+	return func(c *Cron) {
+		c.namespace = namespace
+	}
+}
+
 // WithLock uses the provided Lock.
 func WithLock(lock Lock) CronOption {
 	return func(c *Cron) {
@@ -29,6 +39,32 @@ func WithLocation(loc *time.Location) CronOption {
 	}
 }
 
+// WithParser overrides the parser of the job specs, which accepts six fields with seconds by default.
+// QuartzParser supports the Quartz extensions, and any other parser returning a cron.Schedule may be used.
+// The schedules of other parsers get the times in the location set by WithJobLocation, if the job has one.
+func WithParser(parser cron.ScheduleParser) CronOption {
+	return func(c *Cron) {
+		c.parser = parser
+	}
+}
+
+// WithCalendar excludes the planned runs of all the jobs by the calendar, see also WithJobCalendar.
+func WithCalendar(calendar Calendar) CronOption {
+	return func(c *Cron) {
+		c.calendar = calendar
+	}
+}
+
+// WithIntervalAnchor makes the "@every" specs of all the jobs tick at the anchor plus multiples of the interval,
+// instead of the interval after the start of every instance, see IntervalSchedule.
+// The anchor is shared by the instances, e.g. the zero time for the Unix epoch or a time loaded from a shared store.
+// See also WithJobIntervalAnchor.
+func WithIntervalAnchor(anchor time.Time) CronOption {
+	return func(c *Cron) {
+		c.anchor = &anchor
+	}
+}
+
 // WithContext sets the root context of the cron instance.
 // It will be used as the parent context of all tasks,
 // and when the context is done, the cron will be stopped.
@@ -39,6 +39,36 @@ func TestWithHostname(t *testing.T) {
 	}
 }
 
+func TestWithNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		key       string
+		want      string
+	}{
+		{
+			name:      "regular",
+			namespace: "svc-a:dcron",
+			key:       "cleanup",
+			want:      "svc-a:dcron:cleanup",
+		},
+		{
+			name:      "empty",
+			namespace: "",
+			key:       "cleanup",
+			want:      "cleanup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithNamespace(tt.namespace))
+			if got := c.lockKey(tt.key); got != tt.want {
+				t.Errorf("lockKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWithLock(t *testing.T) {
 	type args struct {
 		lock Lock
@@ -2,6 +2,7 @@ package dcron
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -57,6 +58,35 @@ func Test_Cron(t *testing.T) {
 	}
 }
 
+func Test_Cron_namespace(t *testing.T) {
+	lock := NewMemoryLock(time.Minute)
+	c := NewCron(WithLock(lock), WithNamespace("svc-a:dcron"))
+
+	owners := make(chan string, 1)
+	job := NewJob("cleanup", "* * * * * *", func(ctx context.Context) error {
+		owner, _ := lock.Owner("svc-a:dcron:cleanup")
+		select {
+		case owners <- owner:
+		default:
+		}
+		return nil
+	})
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case owner := <-owners:
+		if owner != c.Hostname() {
+			t.Errorf("owner of the namespaced key = %q, want %q", owner, c.Hostname())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("the job has not run")
+	}
+}
+
 func TestCron_AddJobs(t *testing.T) {
 	c := cron.New(cron.WithSeconds())
 
@@ -165,6 +195,93 @@ func TestCron_AddJobs(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid lock settings",
+			fields: fields{
+				cron: c,
+				lock: &validatingLock{},
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_valid", "* * * * * *", nil, WithJobSettings(time.Minute)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid lock settings",
+			fields: fields{
+				cron: c,
+				lock: &validatingLock{},
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_invalid", "* * * * * *", nil, WithJobSettings("1m")),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid lock settings without lock",
+			fields: fields{
+				cron: c,
+				lock: &validatingLock{},
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_no_lock", "* * * * * *", nil, WithJobSettings("1m"), WithNoLock()),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "time zone prefix",
+			fields: fields{
+				cron: c,
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_tz", "CRON_TZ=Asia/Tokyo 0 0 9 * * *", nil),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bad time zone prefix",
+			fields: fields{
+				cron: c,
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_bad_tz", "CRON_TZ=Mars/Olympus 0 0 9 * * *", nil),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "time zone prefix without schedule",
+			fields: fields{
+				cron: c,
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_tz_only", "CRON_TZ=Asia/Tokyo", nil),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "time zone prefix and job location",
+			fields: fields{
+				cron: c,
+			},
+			args: args{
+				jobs: []Job{
+					NewJob("test_job_tz_twice", "CRON_TZ=Asia/Tokyo 0 0 9 * * *", nil, WithJobLocation(time.UTC)),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -181,6 +298,17 @@ func TestCron_AddJobs(t *testing.T) {
 	}
 }
 
+type validatingLock struct {
+	MemoryLock
+}
+
+func (l *validatingLock) ValidateJob(job JobMeta, jobSetting any) error {
+	if _, ok := jobSetting.(time.Duration); !ok {
+		return errors.New("not a duration")
+	}
+	return nil
+}
+
 func TestCron_Hostname(t *testing.T) {
 	type fields struct {
 		hostname string
@@ -0,0 +1,114 @@
+package dcron
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// validateFailover checks the job with WithFailover can be failed over with the lock of the cron.
+func (c *Cron) validateFailover(j *innerJob) error {
+	if j.staleAfter == 0 && j.heartbeatInterval == 0 {
+		return nil
+	}
+	if j.heartbeatInterval <= 0 || j.staleAfter <= j.heartbeatInterval {
+		return errors.New("failover heartbeat interval should be positive and less than stale after")
+	}
+	if j.noLock || c.lock == nil {
+		return nil
+	}
+	if _, ok := asHeartbeatLock(c.lock); !ok {
+		return errors.New("failover needs a lock implementing HeartbeatLock")
+	}
+	return nil
+}
+
+// failover reports whether the job is failed over when the lock is held by another instance.
+func (j *innerJob) failover() bool {
+	_, ok := asHeartbeatLock(j.cron.lock)
+	return ok && j.staleAfter > 0
+}
+
+// heartbeatWrapper is implemented by the Lock decorators, which implement HeartbeatLock
+// but support it only if the locks they wrap do.
+type heartbeatWrapper interface {
+	supportsHeartbeat() bool
+}
+
+// asHeartbeatLock returns the lock as a HeartbeatLock if it supports heartbeats.
+func asHeartbeatLock(lock Lock) (HeartbeatLock, bool) {
+	heartbeatLock, ok := lock.(HeartbeatLock)
+	if wrapper, isWrapper := lock.(heartbeatWrapper); ok && isWrapper {
+		ok = wrapper.supportsHeartbeat()
+	}
+	return heartbeatLock, ok
+}
+
+// awaitFailover watches the holder of the lock until the context is done,
+// and takes the lock over if the holder stops heartbeating.
+func (j *innerJob) awaitFailover(ctx context.Context, lockKey string) (bool, any) {
+	lock, _ := asHeartbeatLock(j.cron.lock)
+	ticker := time.NewTicker(j.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+
+		locked, lockValue, held := lock.TakeOver(ctx, j.settings, lockKey, j.cron.hostname, j.staleAfter)
+		if locked {
+			atomic.AddInt64(&j.statistics.FailoverTask, 1)
+			if j.logger != nil {
+				j.logger.Infof("task %v was taken over from a stale lock holder", j.key)
+			}
+			if j.slogLogger != nil {
+				j.slogLogger.InfoContext(ctx, "task was taken over from a stale lock holder", SlogKeyTaskName, j.key)
+			}
+			return true, lockValue
+		}
+		if !held {
+			return false, nil
+		}
+	}
+}
+
+// heartbeat refreshes the heartbeat of the taken lock until the returned function is called.
+func (j *innerJob) heartbeat(ctx context.Context, lockKey string, lockValue any) func() {
+	lock, _ := asHeartbeatLock(j.cron.lock)
+	// the heartbeat should last until the task is finished rather than until the task deadline
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(j.heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !lock.Heartbeat(ctx, j.settings, lockKey, j.cron.hostname, lockValue) {
+				if j.logger != nil {
+					j.logger.Errorf("task %v lost its lock, unable to heartbeat", j.key)
+				}
+				if j.slogLogger != nil {
+					j.slogLogger.ErrorContext(ctx, "task lost its lock, unable to heartbeat", SlogKeyTaskName, j.key)
+				}
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
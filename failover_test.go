@@ -0,0 +1,113 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithFailover(t *testing.T) {
+	lock := NewMemoryLock(time.Minute)
+	// the holder crashes right after taking the lock, so it never heartbeats
+	lock.Lock(context.Background(), nil, "job", "crashed")
+
+	var mu sync.Mutex
+	var tasks []Task
+
+	c := NewCron(WithLock(lock), WithHostname("host1"))
+	job := NewJob("job", "* * * * * *", func(ctx context.Context) error {
+		task, _ := TaskFromContext(ctx)
+		mu.Lock()
+		tasks = append(tasks, task)
+		mu.Unlock()
+		return nil
+	}, WithFailover(20*time.Millisecond, 100*time.Millisecond))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+	time.Sleep(2500 * time.Millisecond)
+	<-c.Stop().Done()
+
+	if len(tasks) < 2 {
+		t.Fatalf("the job ran %v times", len(tasks))
+	}
+	if !tasks[0].Failover {
+		t.Errorf("the first task is not a failover one")
+	}
+	if tasks[1].Failover {
+		t.Errorf("the second task is a failover one")
+	}
+	if stats := c.Statistics(); stats.FailoverTask != 1 || stats.MissedTask != 0 {
+		t.Errorf("statistics %+v", stats)
+	}
+}
+
+func TestCron_AddJobs_failover(t *testing.T) {
+	tests := []struct {
+		name    string
+		lock    Lock
+		options []JobOption
+		wantErr bool
+	}{
+		{
+			name:    "heartbeat lock",
+			lock:    NewMemoryLock(time.Minute),
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+		},
+		{
+			name:    "lock without heartbeat",
+			lock:    &failingLock{},
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "lock without heartbeat and no lock",
+			lock:    &failingLock{},
+			options: []JobOption{WithFailover(time.Second, 5*time.Second), WithNoLock()},
+		},
+		{
+			name:    "instrumented heartbeat lock",
+			lock:    NewInstrumentedLock(NewMemoryLock(time.Minute)),
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+		},
+		{
+			name:    "instrumented lock without heartbeat",
+			lock:    NewInstrumentedLock(&failingLock{}),
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "fallback heartbeat locks",
+			lock:    NewFallbackLock(NewMemoryLock(time.Minute), NewMemoryLock(time.Minute)),
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+		},
+		{
+			name:    "fallback to a lock without heartbeat",
+			lock:    NewFallbackLock(NewMemoryLock(time.Minute), &failingLock{}),
+			options: []JobOption{WithFailover(time.Second, 5*time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "stale before heartbeat",
+			lock:    NewMemoryLock(time.Minute),
+			options: []JobOption{WithFailover(5*time.Second, time.Second)},
+			wantErr: true,
+		},
+		{
+			name: "no failover",
+			lock: &failingLock{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithLock(tt.lock))
+			err := c.AddJobs(NewJob("job", "* * * * * *", func(ctx context.Context) error { return nil }, tt.options...))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
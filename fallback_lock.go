@@ -0,0 +1,123 @@
+package dcron
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FallbackLock is a Lock trying the primary Lock first and the secondary one if the primary failed.
+// Only the backend errors reported by FallibleLock cause the fallback,
+// neither a key held by another instance nor an ErrLockSettings error do.
+// Mind that instances which lost the primary backend at different moments may run the same tick twice.
+// It implements HeartbeatLock if both locks do.
+type FallbackLock struct {
+	primary   Lock
+	secondary Lock
+}
+
+type fallbackLockValue struct {
+	lock      Lock
+	lockValue any
+}
+
+// NewFallbackLock returns a FallbackLock over the primary and the secondary locks.
+func NewFallbackLock(primary, secondary Lock) *FallbackLock {
+	return &FallbackLock{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// Lock implements Lock.Lock.
+func (l *FallbackLock) Lock(ctx context.Context, jobSetting any, key, value string) (bool, any) {
+	locked, lockValue, _ := l.TryLock(ctx, jobSetting, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements FallibleLock.TryLock, it fails only if both locks failed.
+func (l *FallbackLock) TryLock(ctx context.Context, jobSetting any, key, value string) (bool, any, error) {
+	locked, lockValue, primaryErr := tryLock(ctx, l.primary, jobSetting, key, value)
+	if primaryErr == nil {
+		return l.result(l.primary, locked, lockValue)
+	}
+	if errors.Is(primaryErr, ErrLockSettings) {
+		return false, nil, primaryErr
+	}
+
+	locked, lockValue, secondaryErr := tryLock(ctx, l.secondary, jobSetting, key, value)
+	if secondaryErr != nil {
+		return false, nil, errors.Join(primaryErr, secondaryErr)
+	}
+	return l.result(l.secondary, locked, lockValue)
+}
+
+// Unlock implements Lock.Unlock, it unlocks the Lock which granted the lock.
+func (l *FallbackLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	if v, ok := lockValue.(fallbackLockValue); ok {
+		v.lock.Unlock(ctx, jobSetting, key, value, v.lockValue)
+	}
+}
+
+// Heartbeat implements HeartbeatLock.Heartbeat, it heartbeats in the Lock which granted the lock.
+func (l *FallbackLock) Heartbeat(ctx context.Context, jobSetting any, key, value string, lockValue any) bool {
+	v, ok := lockValue.(fallbackLockValue)
+	if !ok {
+		return false
+	}
+	lock, ok := asHeartbeatLock(v.lock)
+	return ok && lock.Heartbeat(ctx, jobSetting, key, value, v.lockValue)
+}
+
+// TakeOver implements HeartbeatLock.TakeOver, it takes the key over in the primary Lock,
+// or in the secondary one if the key is not held in the primary one.
+func (l *FallbackLock) TakeOver(ctx context.Context, jobSetting any, key, value string, staleAfter time.Duration) (bool, any, bool) {
+	for _, lock := range []Lock{l.primary, l.secondary} {
+		heartbeatLock, ok := asHeartbeatLock(lock)
+		if !ok {
+			continue
+		}
+		locked, lockValue, held := heartbeatLock.TakeOver(ctx, jobSetting, key, value, staleAfter)
+		if locked {
+			return true, fallbackLockValue{lock: lock, lockValue: lockValue}, true
+		}
+		if held {
+			return false, nil, true
+		}
+	}
+	return false, nil, false
+}
+
+func (l *FallbackLock) supportsHeartbeat() bool {
+	_, primary := asHeartbeatLock(l.primary)
+	_, secondary := asHeartbeatLock(l.secondary)
+	return primary && secondary
+}
+
+// ValidateJob implements LockValidator, the job has to be valid for both locks.
+func (l *FallbackLock) ValidateJob(job JobMeta, jobSetting any) error {
+	for _, lock := range []Lock{l.primary, l.secondary} {
+		if validator, ok := lock.(LockValidator); ok {
+			if err := validator.ValidateJob(job, jobSetting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (l *FallbackLock) result(lock Lock, locked bool, lockValue any) (bool, any, error) {
+	if !locked {
+		return false, nil, nil
+	}
+	return true, fallbackLockValue{lock: lock, lockValue: lockValue}, nil
+}
+
+// tryLock calls FallibleLock.TryLock if the lock implements it, or Lock otherwise.
+func tryLock(ctx context.Context, lock Lock, jobSetting any, key, value string) (bool, any, error) {
+	if fallible, ok := lock.(FallibleLock); ok {
+		return fallible.TryLock(ctx, jobSetting, key, value)
+	}
+	locked, lockValue := lock.Lock(ctx, jobSetting, key, value)
+	return locked, lockValue, nil
+}
@@ -0,0 +1,138 @@
+package dcron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type failingLock struct {
+	err      error
+	unlocked int
+}
+
+func (l *failingLock) Lock(ctx context.Context, jobSetting any, key, value string) (bool, any) {
+	locked, lockValue, _ := l.TryLock(ctx, jobSetting, key, value)
+	return locked, lockValue
+}
+
+func (l *failingLock) TryLock(ctx context.Context, jobSetting any, key, value string) (bool, any, error) {
+	if l.err != nil {
+		return false, nil, l.err
+	}
+	return true, "failing", nil
+}
+
+func (l *failingLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	l.unlocked++
+}
+
+func TestFallbackLock(t *testing.T) {
+	ctx := context.Background()
+	errPrimary := errors.New("primary is down")
+	errSecondary := errors.New("secondary is down")
+
+	t.Run("primary", func(t *testing.T) {
+		primary := &failingLock{}
+		secondary := NewMemoryLock(time.Minute)
+		l := NewFallbackLock(primary, secondary)
+
+		locked, v, err := l.TryLock(ctx, nil, "job", "host1")
+		if !locked || err != nil {
+			t.Fatalf("TryLock() = %v, %v", locked, err)
+		}
+		if _, ok := secondary.Owner("job"); ok {
+			t.Errorf("secondary lock is taken")
+		}
+		l.Unlock(ctx, nil, "job", "host1", v)
+		if primary.unlocked != 1 {
+			t.Errorf("primary unlocked %d times", primary.unlocked)
+		}
+	})
+
+	t.Run("held in primary", func(t *testing.T) {
+		primary := NewMemoryLock(time.Minute)
+		secondary := NewMemoryLock(time.Minute)
+		l := NewFallbackLock(primary, secondary)
+
+		primary.Lock(ctx, nil, "job", "host1")
+		locked, _, err := l.TryLock(ctx, nil, "job", "host2")
+		if locked || err != nil {
+			t.Fatalf("TryLock() = %v, %v", locked, err)
+		}
+		if _, ok := secondary.Owner("job"); ok {
+			t.Errorf("secondary lock is taken")
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		primary := &failingLock{err: errPrimary}
+		secondary := NewMemoryLock(time.Minute)
+		l := NewFallbackLock(primary, secondary)
+
+		locked, v, err := l.TryLock(ctx, nil, "job", "host1")
+		if !locked || err != nil {
+			t.Fatalf("TryLock() = %v, %v", locked, err)
+		}
+		if owner, _ := secondary.Owner("job"); owner != "host1" {
+			t.Errorf("secondary owner = %q", owner)
+		}
+		l.Unlock(ctx, nil, "job", "host1", v)
+		if _, ok := secondary.Owner("job"); ok {
+			t.Errorf("secondary lock is not released")
+		}
+		if primary.unlocked != 0 {
+			t.Errorf("primary unlocked %d times", primary.unlocked)
+		}
+	})
+
+	t.Run("bad settings", func(t *testing.T) {
+		errSettings := fmt.Errorf("%w: no lock ttl", ErrLockSettings)
+		secondary := NewMemoryLock(time.Minute)
+		l := NewFallbackLock(&failingLock{err: errSettings}, secondary)
+
+		locked, _, err := l.TryLock(ctx, nil, "job", "host1")
+		if locked || !errors.Is(err, ErrLockSettings) {
+			t.Fatalf("TryLock() = %v, %v", locked, err)
+		}
+		if _, ok := secondary.Owner("job"); ok {
+			t.Errorf("secondary lock is taken")
+		}
+	})
+
+	t.Run("take over", func(t *testing.T) {
+		primary := NewMemoryLock(time.Minute)
+		secondary := NewMemoryLock(time.Minute)
+		l := NewFallbackLock(primary, secondary)
+
+		// the holder has fallen back to the secondary lock and crashed
+		secondary.Lock(ctx, nil, "job", "host1")
+		locked, v, held := l.TakeOver(ctx, nil, "job", "host2", 0)
+		if !locked || !held {
+			t.Fatalf("TakeOver() = %v, %v", locked, held)
+		}
+		if owner, _ := secondary.Owner("job"); owner != "host2" {
+			t.Errorf("secondary owner = %q", owner)
+		}
+		if !l.Heartbeat(ctx, nil, "job", "host2", v) {
+			t.Errorf("Heartbeat() of the taken key = false")
+		}
+		if _, ok := primary.Owner("job"); ok {
+			t.Errorf("primary lock is taken")
+		}
+	})
+
+	t.Run("both failed", func(t *testing.T) {
+		l := NewFallbackLock(&failingLock{err: errPrimary}, &failingLock{err: errSecondary})
+
+		locked, _, err := l.TryLock(ctx, nil, "job", "host1")
+		if locked {
+			t.Errorf("TryLock() locked")
+		}
+		if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+			t.Errorf("TryLock() error = %v", err)
+		}
+	})
+}
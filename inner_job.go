@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -16,8 +17,26 @@ type JobMeta interface {
 	Key() string
 	// Spec returns the spec of the job.
 	Spec() string
+	// Specs returns the spec of the job followed by the ones added by WithSpecs.
+	Specs() []string
 	// Statistics returns statistics info of the job.
 	Statistics() Statistics
+	// Setting returns the setting of the job stored by WithSetting, use SettingKey.Get for the typed value.
+	Setting(key any) (any, bool)
+	// NextRuns returns the next n planned runs of the job in its time zone, or nil if the job is not scheduled.
+	NextRuns(n int) []time.Time
+	// Window returns the active window of the job, which is zero if the job is always active.
+	Window() Window
+	// Next returns the next planned run of the job in its time zone, or the zero time if the job is not scheduled.
+	Next() time.Time
+	// Prev returns the last planned run of the job in its time zone, or the zero time if it has not run yet.
+	Prev() time.Time
+	// IsRunning returns true if a task of the job is being run by this instance.
+	IsRunning() bool
+	// LastTask returns the last finished task of the job, including the skipped and the missed ones.
+	LastTask() (Task, bool)
+	// LastSuccess returns the last task of the job which has been run without an error.
+	LastSuccess() (Task, bool)
 }
 
 type spanStarter func(ctx context.Context) (context.Context, any)
@@ -42,6 +61,25 @@ type innerJob struct {
 	logger        Logger
 	slogLogger    SlogLogger
 	settings      any
+	keyedSettings map[any]any
+
+	heartbeatInterval time.Duration
+	staleAfter        time.Duration
+
+	location *time.Location
+	calendar Calendar
+	window   Window
+
+	jitter       time.Duration
+	randomJitter bool
+
+	specs  []string
+	anchor *time.Time
+
+	running     int32
+	lastMu      sync.Mutex
+	lastTask    *Task
+	lastSuccess *Task
 }
 
 const (
@@ -50,6 +88,9 @@ const (
 	SlogKeyMaxAttempts = "dcron_task_max_attempts"
 	SlogKeyError       = "dcron_task_error"
 	SlogKeyDuration    = "dcron_sleep_duration"
+	SlogKeyLockResult  = "dcron_lock_result"
+	SlogKeyLockLatency = "dcron_lock_latency"
+	SlogKeySkipReason  = "dcron_task_skip_reason"
 )
 
 // Key implements JobMeta.Key.
@@ -62,15 +103,99 @@ func (j *innerJob) Spec() string {
 	return j.spec
 }
 
+// Specs implements JobMeta.Specs.
+func (j *innerJob) Specs() []string {
+	return append([]string{j.spec}, j.specs...)
+}
+
 // Statistics implements JobMeta.Statistics.
 func (j *innerJob) Statistics() Statistics {
 	return j.statistics
 }
 
+// Setting implements JobMeta.Setting.
+func (j *innerJob) Setting(key any) (any, bool) {
+	value, ok := j.keyedSettings[key]
+	return value, ok
+}
+
+// NextRuns implements JobMeta.NextRuns.
+func (j *innerJob) NextRuns(n int) []time.Time {
+	entry := j.entryGetter.Entry(j.entryID)
+	if entry.Schedule == nil {
+		return nil
+	}
+	from := time.Now()
+	if j.cron.location != nil {
+		from = from.In(j.cron.location)
+	}
+	location := j.location
+	if location == nil {
+		location = from.Location()
+	}
+	return nextRuns(entry.Schedule, from, location, n)
+}
+
+// Window implements JobMeta.Window.
+func (j *innerJob) Window() Window {
+	return j.window
+}
+
+// Next implements JobMeta.Next.
+func (j *innerJob) Next() time.Time {
+	return j.inLocation(j.entryGetter.Entry(j.entryID).Next)
+}
+
+// Prev implements JobMeta.Prev.
+func (j *innerJob) Prev() time.Time {
+	return j.inLocation(j.entryGetter.Entry(j.entryID).Prev)
+}
+
+// IsRunning implements JobMeta.IsRunning.
+func (j *innerJob) IsRunning() bool {
+	return atomic.LoadInt32(&j.running) > 0
+}
+
+// LastTask implements JobMeta.LastTask.
+func (j *innerJob) LastTask() (Task, bool) {
+	j.lastMu.Lock()
+	defer j.lastMu.Unlock()
+	if j.lastTask == nil {
+		return Task{}, false
+	}
+	return *j.lastTask, true
+}
+
+// LastSuccess implements JobMeta.LastSuccess.
+func (j *innerJob) LastSuccess() (Task, bool) {
+	j.lastMu.Lock()
+	defer j.lastMu.Unlock()
+	if j.lastSuccess == nil {
+		return Task{}, false
+	}
+	return *j.lastSuccess, true
+}
+
+// intervalAnchor returns the anchor of the "@every" specs of the job, or of the cron if the job has none.
+func (j *innerJob) intervalAnchor() *time.Time {
+	if j.anchor != nil {
+		return j.anchor
+	}
+	return j.cron.anchor
+}
+
+// inLocation converts a time of the cron entry to the time zone of the job.
+func (j *innerJob) inLocation(t time.Time) time.Time {
+	if j.location == nil || t.IsZero() {
+		return t
+	}
+	return t.In(j.location)
+}
+
 func (j *innerJob) Run() {
 	c := j.cron
 	entry := j.entryGetter.Entry(j.entryID)
-	planAt := entry.Prev
+	planAt := j.inLocation(entry.Prev)
 	nextAt := entry.Next
 	key := j.key
 
@@ -95,7 +220,15 @@ func (j *innerJob) Run() {
 		ctx = j.deriveContext(ctx, task)
 	}
 
-	if j.ctxBefore != nil {
+	if excluded, reason := j.excluded(task.PlanAt); excluded {
+		task.Skipped = true
+		task.SkipReason = reason
+		atomic.AddInt64(&j.statistics.SkippedTask, 1)
+	} else if !j.window.Active(task.PlanAt) {
+		task.Skipped = true
+		task.SkipReason = "outside of active window"
+		atomic.AddInt64(&j.statistics.SkippedTask, 1)
+	} else if j.ctxBefore != nil {
 		if j.ctxBefore(ctx, task) {
 			task.Skipped = true
 			atomic.AddInt64(&j.statistics.SkippedTask, 1)
@@ -113,6 +246,7 @@ func (j *innerJob) Run() {
 	if !task.Skipped {
 		var lockValue any
 		var lockTaken bool
+		lockKey := c.lockKey(task.Key)
 
 		shouldUseLock := func() bool {
 			return !j.noLock && j.cron.lock != nil
@@ -122,79 +256,111 @@ func (j *innerJob) Run() {
 				return true
 			}
 
-			lockTaken, lockValue = j.cron.lock.Lock(ctx, j.settings, task.Key, c.hostname)
+			lockTaken, lockValue = j.cron.lock.Lock(ctx, j.settings, lockKey, c.hostname)
+			if !lockTaken && j.failover() {
+				lockTaken, lockValue = j.awaitFailover(ctx, lockKey)
+				if lockTaken {
+					task.Failover = true
+					ctx = context.WithValue(ctx, keyContextTask, task)
+				}
+			}
 			return lockTaken
 		}
 		needExec := shouldExec()
 		if lockTaken {
-			defer j.cron.lock.Unlock(ctx, j.settings, task.Key, c.hostname, lockValue)
+			defer j.cron.lock.Unlock(ctx, j.settings, lockKey, c.hostname, lockValue)
+			if j.failover() {
+				defer j.heartbeat(ctx, lockKey, lockValue)()
+			}
 		}
 
 		if needExec {
-			beginAt := time.Now()
-			task.BeginAt = &beginAt
+			// the lock is taken at the nominal tick, so the delayed start does not let another instance run it
+			offset := j.jitterOffset()
+			if offset > 0 && !waitJitter(ctx, offset) {
+				task.Skipped = true
+				task.SkipReason = "jitter wait cancelled"
+				atomic.AddInt64(&j.statistics.SkippedTask, 1)
 
-			for i := 0; i < j.retryTimes; i++ {
 				if j.logger != nil {
-					j.logger.Infof("starting task %v: %v / %v", task.Key, (i + 1), j.retryTimes)
+					j.logger.Infof("task %v was skipped: %v", task.Key, task.SkipReason)
 				}
 				if j.slogLogger != nil {
-					j.slogLogger.InfoContext(ctx, "starting task", SlogKeyTaskName, task.Key, SlogKeyAttempt, (i + 1), SlogKeyMaxAttempts, j.retryTimes)
+					j.slogLogger.InfoContext(ctx, "task was skipped", SlogKeyTaskName, task.Key, SlogKeySkipReason, task.SkipReason)
 				}
-
-				task.Return = safeRun(ctx, j.run)
-				atomic.AddInt64(&j.statistics.TotalRun, 1)
-				if i > 0 {
-					atomic.AddInt64(&j.statistics.RetriedRun, 1)
+			} else {
+				if offset > 0 {
+					task.JitterOffset = offset
+					ctx = context.WithValue(ctx, keyContextTask, task)
 				}
-				task.TriedTimes++
-				if task.Return == nil {
-					atomic.AddInt64(&j.statistics.PassedRun, 1)
-					if j.logger != nil {
-						j.logger.Infof("task %v was finished successfully", task.Key)
-					}
-					if j.slogLogger != nil {
-						j.slogLogger.InfoContext(ctx, "task was finished successfully", SlogKeyTaskName, task.Key)
-					}
+				atomic.AddInt32(&j.running, 1)
 
-					break // prevents incrementing FailedRun
-				} else {
+				beginAt := time.Now()
+				task.BeginAt = &beginAt
+
+				for i := 0; i < j.retryTimes; i++ {
 					if j.logger != nil {
-						j.logger.Errorf("an error occurred during task %v execution: %v", task.Key, task.Return)
+						j.logger.Infof("starting task %v: %v / %v", task.Key, (i + 1), j.retryTimes)
 					}
 					if j.slogLogger != nil {
-						j.slogLogger.ErrorContext(ctx, "an error occurred during task execution", SlogKeyTaskName, task.Key, SlogKeyError, task.Return)
+						j.slogLogger.InfoContext(ctx, "starting task", SlogKeyTaskName, task.Key, SlogKeyAttempt, (i + 1), SlogKeyMaxAttempts, j.retryTimes)
 					}
-				}
-				atomic.AddInt64(&j.statistics.FailedRun, 1)
-				if ctx.Err() != nil {
-					if j.logger != nil {
-						j.logger.Errorf("got error in the context task %v execution: %v", task.Key, ctx.Err())
+
+					task.Return = safeRun(ctx, j.run)
+					atomic.AddInt64(&j.statistics.TotalRun, 1)
+					if i > 0 {
+						atomic.AddInt64(&j.statistics.RetriedRun, 1)
 					}
-					if j.slogLogger != nil {
-						j.slogLogger.ErrorContext(ctx, "got error in the context", SlogKeyTaskName, task.Key, SlogKeyError, ctx.Err())
+					task.TriedTimes++
+					if task.Return == nil {
+						atomic.AddInt64(&j.statistics.PassedRun, 1)
+						if j.logger != nil {
+							j.logger.Infof("task %v was finished successfully", task.Key)
+						}
+						if j.slogLogger != nil {
+							j.slogLogger.InfoContext(ctx, "task was finished successfully", SlogKeyTaskName, task.Key)
+						}
+
+						break // prevents incrementing FailedRun
+					} else {
+						if j.logger != nil {
+							j.logger.Errorf("an error occurred during task %v execution: %v", task.Key, task.Return)
+						}
+						if j.slogLogger != nil {
+							j.slogLogger.ErrorContext(ctx, "an error occurred during task execution", SlogKeyTaskName, task.Key, SlogKeyError, task.Return)
+						}
 					}
-					break
-				}
-				if j.retryInterval != nil {
-					interval := j.retryInterval(task.TriedTimes)
-					deadline, _ := ctx.Deadline()
-					if -time.Since(deadline) < interval {
+					atomic.AddInt64(&j.statistics.FailedRun, 1)
+					if ctx.Err() != nil {
+						if j.logger != nil {
+							j.logger.Errorf("got error in the context task %v execution: %v", task.Key, ctx.Err())
+						}
+						if j.slogLogger != nil {
+							j.slogLogger.ErrorContext(ctx, "got error in the context", SlogKeyTaskName, task.Key, SlogKeyError, ctx.Err())
+						}
 						break
 					}
-					if j.logger != nil {
-						j.logger.Infof("sleeping % for task %v before retry", interval, task.Key)
-					}
-					if j.slogLogger != nil {
-						j.slogLogger.InfoContext(ctx, "sleeping before retry", SlogKeyTaskName, task.Key, SlogKeyDuration, interval)
-					}
+					if j.retryInterval != nil {
+						interval := j.retryInterval(task.TriedTimes)
+						deadline, _ := ctx.Deadline()
+						if -time.Since(deadline) < interval {
+							break
+						}
+						if j.logger != nil {
+							j.logger.Infof("sleeping % for task %v before retry", interval, task.Key)
+						}
+						if j.slogLogger != nil {
+							j.slogLogger.InfoContext(ctx, "sleeping before retry", SlogKeyTaskName, task.Key, SlogKeyDuration, interval)
+						}
 
-					time.Sleep(interval)
+						time.Sleep(interval)
+					}
 				}
-			}
 
-			endAt := time.Now()
-			task.EndAt = &endAt
+				endAt := time.Now()
+				task.EndAt = &endAt
+				atomic.AddInt32(&j.running, -1)
+			}
 		} else {
 			task.Missed = true
 			atomic.AddInt64(&j.statistics.MissedTask, 1)
@@ -206,6 +372,13 @@ func (j *innerJob) Run() {
 				j.slogLogger.InfoContext(ctx, "task was missed because of lock", SlogKeyTaskName, task.Key)
 			}
 		}
+	} else if task.SkipReason != "" {
+		if j.logger != nil {
+			j.logger.Infof("task %v was skipped: %v", task.Key, task.SkipReason)
+		}
+		if j.slogLogger != nil {
+			j.slogLogger.InfoContext(ctx, "task was skipped", SlogKeyTaskName, task.Key, SlogKeySkipReason, task.SkipReason)
+		}
 	} else {
 		if j.logger != nil {
 			j.logger.Infof("task %v was skipped by beforeFunc", task.Key)
@@ -226,6 +399,39 @@ func (j *innerJob) Run() {
 			atomic.AddInt64(&j.statistics.FailedTask, 1)
 		}
 	}
+
+	j.lastMu.Lock()
+	j.lastTask = &task
+	if !task.Skipped && !task.Missed && task.Return == nil {
+		j.lastSuccess = &task
+	}
+	j.lastMu.Unlock()
+
+	if j.window.ended(nextAt) {
+		if j.logger != nil {
+			j.logger.Infof("job %v is unregistered at the end of its active window", task.Key)
+		}
+		if j.slogLogger != nil {
+			j.slogLogger.InfoContext(ctx, "job is unregistered at the end of its active window", SlogKeyTaskName, task.Key)
+		}
+		c.removeJob(j)
+	}
+}
+
+// excluded checks the planned run against the calendars of the cron and of the job.
+func (j *innerJob) excluded(planAt time.Time) (bool, string) {
+	for _, calendar := range []Calendar{j.cron.calendar, j.calendar} {
+		if calendar == nil {
+			continue
+		}
+		if excluded, reason := calendar.Excluded(planAt); excluded {
+			if reason == "" {
+				reason = "excluded by calendar"
+			}
+			return true, reason
+		}
+	}
+	return false, ""
 }
 
 func safeRun(ctx context.Context, run RunFunc) (err error) {
@@ -509,3 +509,84 @@ func Test_innerJob_Spec(t *testing.T) {
 		})
 	}
 }
+
+func Test_innerJob_NextPrev(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	prev := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := prev.Add(time.Hour)
+
+	mockEntryGetter := mock_dcron.NewMockentryGetter(ctrl)
+	mockEntryGetter.EXPECT().
+		Entry(cron.EntryID(1)).
+		Return(cron.Entry{ID: 1, Next: next, Prev: prev}).
+		Times(2)
+
+	j := &innerJob{entryID: 1, entryGetter: mockEntryGetter, location: tokyo}
+	if got := j.Next(); !got.Equal(next) || got.Location() != tokyo {
+		t.Errorf("Next() = %v, want %v in %v", got, next, tokyo)
+	}
+	if got := j.Prev(); !got.Equal(prev) || got.Location() != tokyo {
+		t.Errorf("Prev() = %v, want %v in %v", got, prev, tokyo)
+	}
+}
+
+func Test_innerJob_LastTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntryGetter := mock_dcron.NewMockentryGetter(ctrl)
+	mockEntryGetter.EXPECT().
+		Entry(gomock.Any()).
+		DoAndReturn(func(id cron.EntryID) cron.Entry {
+			now := time.Now()
+			return cron.Entry{ID: id, Next: now.Add(time.Second), Prev: now}
+		}).
+		AnyTimes()
+
+	var fail bool
+	j := &innerJob{
+		cron:        NewCron(),
+		entryID:     1,
+		entryGetter: mockEntryGetter,
+		key:         "job",
+		retryTimes:  1,
+	}
+	j.run = func(ctx context.Context) error {
+		if !j.IsRunning() {
+			t.Error("IsRunning() = false while running")
+		}
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	if _, ok := j.LastTask(); ok {
+		t.Error("LastTask() of a job which has not run")
+	}
+
+	j.Run()
+	success, ok := j.LastSuccess()
+	if !ok || success.Return != nil || success.EndAt == nil {
+		t.Fatalf("LastSuccess() = %v, %v", success, ok)
+	}
+
+	fail = true
+	j.Run()
+	if j.IsRunning() {
+		t.Error("IsRunning() = true after running")
+	}
+	last, ok := j.LastTask()
+	if !ok || last.Return == nil {
+		t.Errorf("LastTask() = %v, %v, want the failed task", last, ok)
+	}
+	if got, _ := j.LastSuccess(); !got.PlanAt.Equal(success.PlanAt) {
+		t.Errorf("LastSuccess() = %v, want %v", got, success)
+	}
+}
@@ -0,0 +1,221 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LockResult is the outcome of a lock acquisition.
+type LockResult string
+
+const (
+	LockWon    LockResult = "won"    // the key was taken by this instance
+	LockLost   LockResult = "lost"   // the key is held by another instance
+	LockFailed LockResult = "failed" // the backend failed, reported only by a FallibleLock
+)
+
+// LockObservation describes one lock acquisition of an InstrumentedLock.
+type LockObservation struct {
+	Key     string
+	Value   string
+	Result  LockResult
+	Latency time.Duration
+	Err     error // set if Result is LockFailed
+}
+
+// LockObserver receives every LockObservation of an InstrumentedLock, e.g. to export it as metrics.
+type LockObserver func(ctx context.Context, observation LockObservation)
+
+// LockStatistics records lock acquisitions of an InstrumentedLock.
+type LockStatistics struct {
+	Won     int64         // Number of acquisitions which took the key
+	Lost    int64         // Number of acquisitions which found the key held by another instance
+	Failed  int64         // Number of acquisitions which failed due to backend errors
+	Latency time.Duration // Total time spent on acquisitions
+}
+
+// InstrumentedLock wraps a Lock, recording latency and outcome of every acquisition, including takeovers.
+// The outcomes are counted in Statistics, passed to the observers and logged, the failures as errors.
+// It implements HeartbeatLock if the wrapped lock does.
+type InstrumentedLock struct {
+	lock       Lock
+	now        func() time.Time
+	logger     Logger
+	slogLogger SlogLogger
+	observers  []LockObserver
+
+	mu         sync.Mutex
+	statistics LockStatistics
+}
+
+// NewInstrumentedLock returns an InstrumentedLock wrapping the lock.
+func NewInstrumentedLock(lock Lock, options ...InstrumentedLockOption) *InstrumentedLock {
+	ret := &InstrumentedLock{
+		lock: lock,
+		now:  time.Now,
+	}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+// InstrumentedLockOption represents a modification to the default behavior of an InstrumentedLock.
+type InstrumentedLockOption func(l *InstrumentedLock)
+
+// WithLockLog sets the classic logger, instead of the one of the job of the task, which is the one of the Cron by default.
+func WithLockLog(logger Logger) InstrumentedLockOption {
+	return func(l *InstrumentedLock) {
+		l.logger = logger
+	}
+}
+
+// WithLockSLog sets the structured logger, instead of the one of the job of the task, which is the one of the Cron by default.
+func WithLockSLog(logger SlogLogger) InstrumentedLockOption {
+	return func(l *InstrumentedLock) {
+		l.slogLogger = logger
+	}
+}
+
+// WithLockObserver adds an observer, it is called synchronously after every acquisition.
+func WithLockObserver(observer LockObserver) InstrumentedLockOption {
+	return func(l *InstrumentedLock) {
+		l.observers = append(l.observers, observer)
+	}
+}
+
+// Lock implements Lock.Lock.
+func (l *InstrumentedLock) Lock(ctx context.Context, jobSetting any, key, value string) (bool, any) {
+	locked, lockValue, _ := l.TryLock(ctx, jobSetting, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements FallibleLock.TryLock.
+// Backend errors are told apart only if the wrapped lock is a FallibleLock.
+func (l *InstrumentedLock) TryLock(ctx context.Context, jobSetting any, key, value string) (bool, any, error) {
+	start := l.now()
+	locked, lockValue, err := tryLock(ctx, l.lock, jobSetting, key, value)
+
+	observation := LockObservation{
+		Key:     key,
+		Value:   value,
+		Result:  LockLost,
+		Latency: l.now().Sub(start),
+		Err:     err,
+	}
+	switch {
+	case err != nil:
+		observation.Result = LockFailed
+	case locked:
+		observation.Result = LockWon
+	}
+	l.observe(ctx, observation)
+
+	return locked, lockValue, err
+}
+
+// Unlock implements Lock.Unlock.
+func (l *InstrumentedLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	l.lock.Unlock(ctx, jobSetting, key, value, lockValue)
+}
+
+// Heartbeat implements HeartbeatLock.Heartbeat if the wrapped lock does.
+func (l *InstrumentedLock) Heartbeat(ctx context.Context, jobSetting any, key, value string, lockValue any) bool {
+	lock, ok := asHeartbeatLock(l.lock)
+	return ok && lock.Heartbeat(ctx, jobSetting, key, value, lockValue)
+}
+
+// TakeOver implements HeartbeatLock.TakeOver if the wrapped lock does.
+// A takeover is recorded as an acquisition, which is won if the key is taken over.
+func (l *InstrumentedLock) TakeOver(ctx context.Context, jobSetting any, key, value string, staleAfter time.Duration) (bool, any, bool) {
+	lock, ok := asHeartbeatLock(l.lock)
+	if !ok {
+		return false, nil, false
+	}
+
+	start := l.now()
+	taken, lockValue, held := lock.TakeOver(ctx, jobSetting, key, value, staleAfter)
+
+	observation := LockObservation{
+		Key:     key,
+		Value:   value,
+		Result:  LockLost,
+		Latency: l.now().Sub(start),
+	}
+	if taken {
+		observation.Result = LockWon
+	}
+	l.observe(ctx, observation)
+
+	return taken, lockValue, held
+}
+
+func (l *InstrumentedLock) supportsHeartbeat() bool {
+	_, ok := asHeartbeatLock(l.lock)
+	return ok
+}
+
+// ValidateJob implements LockValidator if the wrapped lock does.
+func (l *InstrumentedLock) ValidateJob(job JobMeta, jobSetting any) error {
+	if validator, ok := l.lock.(LockValidator); ok {
+		return validator.ValidateJob(job, jobSetting)
+	}
+	return nil
+}
+
+// Statistics returns the acquisitions recorded so far.
+func (l *InstrumentedLock) Statistics() LockStatistics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.statistics
+}
+
+func (l *InstrumentedLock) observe(ctx context.Context, observation LockObservation) {
+	l.mu.Lock()
+	switch observation.Result {
+	case LockWon:
+		l.statistics.Won++
+	case LockLost:
+		l.statistics.Lost++
+	case LockFailed:
+		l.statistics.Failed++
+	}
+	l.statistics.Latency += observation.Latency
+	l.mu.Unlock()
+
+	logger, slogLogger := l.loggers(ctx)
+	if observation.Err != nil {
+		if logger != nil {
+			logger.Errorf("unable to take lock %v in %v: %v", observation.Key, observation.Latency, observation.Err)
+		}
+		if slogLogger != nil {
+			slogLogger.ErrorContext(ctx, "unable to take lock", SlogKeyTaskName, observation.Key, SlogKeyLockLatency, observation.Latency, SlogKeyError, observation.Err)
+		}
+	} else {
+		if logger != nil {
+			logger.Infof("lock %v %v in %v", observation.Key, observation.Result, observation.Latency)
+		}
+		if slogLogger != nil {
+			slogLogger.InfoContext(ctx, "lock acquisition", SlogKeyTaskName, observation.Key, SlogKeyLockResult, string(observation.Result), SlogKeyLockLatency, observation.Latency)
+		}
+	}
+
+	for _, observer := range l.observers {
+		observer(ctx, observation)
+	}
+}
+
+// loggers returns the loggers set by WithLockLog and WithLockSLog, or the ones of the job of the task in the context.
+func (l *InstrumentedLock) loggers(ctx context.Context) (Logger, SlogLogger) {
+	if l.logger != nil || l.slogLogger != nil {
+		return l.logger, l.slogLogger
+	}
+	task, _ := TaskFromContext(ctx)
+	if j, ok := task.Job.(*innerJob); ok {
+		return j.logger, j.slogLogger
+	}
+	return nil, nil
+}
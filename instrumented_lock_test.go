@@ -0,0 +1,124 @@
+package dcron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstrumentedLock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	errBackend := errors.New("backend is down")
+
+	backend := &failingLock{}
+	var observations []LockObservation
+	l := NewInstrumentedLock(backend, WithLockObserver(func(ctx context.Context, observation LockObservation) {
+		observations = append(observations, observation)
+	}))
+	l.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	if locked, _ := l.Lock(ctx, nil, "job", "host1"); !locked {
+		t.Fatalf("Lock() is not locked")
+	}
+	backend.err = errBackend
+	if locked, _, err := l.TryLock(ctx, nil, "job", "host1"); locked || !errors.Is(err, errBackend) {
+		t.Fatalf("TryLock() = %v, %v", locked, err)
+	}
+
+	memory := NewMemoryLock(time.Minute)
+	memory.Lock(ctx, nil, "job", "host1")
+	l.lock = memory
+	if locked, _ := l.Lock(ctx, nil, "job", "host2"); locked {
+		t.Fatalf("Lock() is locked")
+	}
+
+	wantResults := []LockResult{LockWon, LockFailed, LockLost}
+	if len(observations) != len(wantResults) {
+		t.Fatalf("got %d observations, want %d", len(observations), len(wantResults))
+	}
+	for i, want := range wantResults {
+		if observations[i].Result != want {
+			t.Errorf("observations[%d].Result = %v, want %v", i, observations[i].Result, want)
+		}
+		if observations[i].Latency != time.Millisecond {
+			t.Errorf("observations[%d].Latency = %v", i, observations[i].Latency)
+		}
+	}
+	if observations[1].Err != errBackend {
+		t.Errorf("observations[1].Err = %v", observations[1].Err)
+	}
+
+	want := LockStatistics{Won: 1, Lost: 1, Failed: 1, Latency: 3 * time.Millisecond}
+	if got := l.Statistics(); got != want {
+		t.Errorf("Statistics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestInstrumentedLock_log(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewCron(WithSLog(slog.New(slog.NewTextHandler(&buf, nil))))
+	if err := c.AddJobs(NewJob("job", "* * * * * *", nil)); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), keyContextTask, Task{Job: c.Jobs()[0]})
+
+	backend := &failingLock{}
+	l := NewInstrumentedLock(backend)
+	l.Lock(ctx, nil, "job", "host1")
+	if !strings.Contains(buf.String(), "level=INFO") || !strings.Contains(buf.String(), SlogKeyLockResult+"=won") ||
+		!strings.Contains(buf.String(), SlogKeyLockLatency+"=") {
+		t.Errorf("a won lock is not logged with its latency by the logger of the cron: %s", buf.String())
+	}
+
+	backend.err = errors.New("backend is down")
+	l.Lock(ctx, nil, "job", "host1")
+	if !strings.Contains(buf.String(), "unable to take lock") || !strings.Contains(buf.String(), "backend is down") {
+		t.Errorf("a failed lock is not logged by the logger of the cron: %s", buf.String())
+	}
+}
+
+func TestInstrumentedLock_heartbeat(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := asHeartbeatLock(NewInstrumentedLock(&failingLock{})); ok {
+		t.Error("a lock without heartbeats supports them when instrumented")
+	}
+
+	var observations []LockObservation
+	l := NewInstrumentedLock(NewMemoryLock(time.Minute), WithLockObserver(func(ctx context.Context, observation LockObservation) {
+		observations = append(observations, observation)
+	}))
+	if _, ok := asHeartbeatLock(l); !ok {
+		t.Fatal("a heartbeat lock does not support heartbeats when instrumented")
+	}
+	_, v := l.Lock(ctx, nil, "job", "host1")
+	if !l.Heartbeat(ctx, nil, "job", "host1", v) {
+		t.Error("Heartbeat() of a held key = false")
+	}
+	if locked, _, held := l.TakeOver(ctx, nil, "job", "host2", time.Hour); locked || !held {
+		t.Errorf("TakeOver() of a heartbeating key = %v, %v", locked, held)
+	}
+	if locked, _, held := l.TakeOver(ctx, nil, "job", "host2", 0); !locked || !held {
+		t.Errorf("TakeOver() of a stale key = %v, %v", locked, held)
+	}
+
+	wantResults := []LockResult{LockWon, LockLost, LockWon}
+	if len(observations) != len(wantResults) {
+		t.Fatalf("got %d observations, want %d", len(observations), len(wantResults))
+	}
+	for i, want := range wantResults {
+		if observations[i].Result != want {
+			t.Errorf("observations[%d].Result = %v, want %v", i, observations[i].Result, want)
+		}
+	}
+	if got := l.Statistics(); got.Won != 2 || got.Lost != 1 {
+		t.Errorf("Statistics() = %+v, want 2 won and 1 lost", got)
+	}
+}
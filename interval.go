@@ -0,0 +1,39 @@
+package dcron
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// IntervalSchedule runs at fixed-rate ticks of the interval counted from the anchor,
+// so every instance computes the same ticks regardless of when it has been started.
+// The zero anchor is the Unix epoch, which makes e.g. an hour interval tick at the start of every UTC hour.
+type IntervalSchedule struct {
+	Interval time.Duration
+	Anchor   time.Time
+}
+
+// Next implements cron.Schedule.Next, it returns the zero time if the interval is not positive.
+func (s IntervalSchedule) Next(t time.Time) time.Time {
+	if s.Interval <= 0 {
+		return time.Time{}
+	}
+	anchor := s.Anchor
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+	if t.Before(anchor) {
+		return anchor.In(t.Location())
+	}
+	ticks := t.Sub(anchor) / s.Interval
+	return anchor.Add((ticks + 1) * s.Interval).In(t.Location())
+}
+
+// anchorInterval replaces the "@every" schedule by the IntervalSchedule of the anchor if it is set.
+func anchorInterval(schedule cron.Schedule, anchor *time.Time) cron.Schedule {
+	if s, ok := schedule.(cron.ConstantDelaySchedule); ok && anchor != nil {
+		return IntervalSchedule{Interval: s.Delay, Anchor: *anchor}
+	}
+	return schedule
+}
@@ -0,0 +1,122 @@
+package dcron
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntervalSchedule_Next(t *testing.T) {
+	anchor := time.Date(2024, 1, 1, 0, 0, 7, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule IntervalSchedule
+		t        time.Time
+		want     time.Time
+	}{
+		{
+			name:     "epoch",
+			schedule: IntervalSchedule{Interval: time.Hour},
+			t:        time.Date(2024, 1, 1, 10, 20, 30, 0, time.UTC),
+			want:     time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "on tick",
+			schedule: IntervalSchedule{Interval: 10 * time.Minute, Anchor: anchor},
+			t:        time.Date(2024, 1, 1, 0, 10, 7, 0, time.UTC),
+			want:     time.Date(2024, 1, 1, 0, 20, 7, 0, time.UTC),
+		},
+		{
+			name:     "between ticks",
+			schedule: IntervalSchedule{Interval: 10 * time.Minute, Anchor: anchor},
+			t:        time.Date(2024, 3, 5, 12, 34, 0, 0, time.UTC),
+			want:     time.Date(2024, 3, 5, 12, 40, 7, 0, time.UTC),
+		},
+		{
+			name:     "before anchor",
+			schedule: IntervalSchedule{Interval: 10 * time.Minute, Anchor: anchor},
+			t:        time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			want:     anchor,
+		},
+		{
+			name:     "no interval",
+			schedule: IntervalSchedule{Anchor: anchor},
+			t:        anchor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.t); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithIntervalAnchor(t *testing.T) {
+	from := time.Date(2024, 1, 1, 10, 21, 30, 0, time.UTC)
+
+	// instances started at different times compute the same ticks
+	for _, start := range []time.Duration{0, 3 * time.Minute, 7*time.Minute + 11*time.Second} {
+		got, err := NewCron(WithLocation(time.UTC), WithIntervalAnchor(time.Time{})).Preview("@every 10m", from.Add(start), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []time.Time{
+			time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 10, 40, 0, 0, time.UTC),
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Preview() from %v = %v, want %v", from.Add(start), got, want)
+		}
+	}
+
+	got, err := NewCron(WithLocation(time.UTC)).Preview("@every 10m", from, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{from.Add(10 * time.Minute)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview() without anchor = %v, want %v", got, want)
+	}
+}
+
+func TestWithJobIntervalAnchor(t *testing.T) {
+	anchor := time.Now().Truncate(time.Hour).Add(5 * time.Second)
+	c := NewCron(WithIntervalAnchor(time.Time{}))
+	if err := c.AddJobs(NewJob("job", "@every 1h", nil, WithJobIntervalAnchor(anchor))); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := c.Jobs()[0].NextRuns(2)
+	if len(runs) != 2 {
+		t.Fatalf("NextRuns() = %v", runs)
+	}
+	for _, run := range runs {
+		if run.Sub(anchor)%time.Hour != 0 {
+			t.Errorf("run %v is not anchored to %v", run, anchor)
+		}
+	}
+}
+
+func TestCron_AddJobs_intervalAnchor(t *testing.T) {
+	anchor := WithJobIntervalAnchor(time.Time{})
+	next := func(t time.Time) time.Time { return t.Add(time.Minute) }
+	tests := []struct {
+		name    string
+		job     Job
+		wantErr bool
+	}{
+		{name: "every", job: NewJob("job", "@every 10m", nil, anchor)},
+		{name: "every in more specs", job: NewJob("job", "0 0 * * * *", nil, WithSpecs("@every 10m"), anchor)},
+		{name: "no every", job: NewJob("job", "0 0 * * * *", nil, anchor), wantErr: true},
+		{name: "custom schedule", job: NewScheduleJob("job", "every minute", next, nil, anchor), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCron().AddJobs(tt.job)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package dcron
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// jitterOffset returns the delay of the task start after the nominal tick.
+func (j *innerJob) jitterOffset() time.Duration {
+	if j.jitter <= 0 {
+		return 0
+	}
+	if j.randomJitter {
+		return time.Duration(rand.Int64N(int64(j.jitter)))
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(j.key))
+	return time.Duration(h.Sum64() % uint64(j.jitter))
+}
+
+// waitJitter delays the task by the jitter offset, but not beyond the deadline of the task.
+// It returns false if the wait was cut short by the deadline or by the cron stopping.
+func waitJitter(ctx context.Context, offset time.Duration) bool {
+	timer := time.NewTimer(offset)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// validateJitter checks the jitter is less than the intervals between the next runs of the schedule,
+// otherwise the delayed start would run into the next tick.
+func (j *innerJob) validateJitter(schedule cron.Schedule) error {
+	if j.jitter <= 0 {
+		return nil
+	}
+	prev := schedule.Next(time.Now())
+	for i := 0; i < 10 && !prev.IsZero(); i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(prev) <= j.jitter {
+			return fmt.Errorf("jitter %v should be less than the interval between runs %v", j.jitter, next.Sub(prev))
+		}
+		prev = next
+	}
+	return nil
+}
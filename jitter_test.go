@@ -0,0 +1,138 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nkonev/dcron/mock_dcron"
+
+	"go.uber.org/mock/gomock"
+)
+
+func Test_innerJob_jitterOffset(t *testing.T) {
+	j1 := &innerJob{key: "job1", jitter: time.Minute}
+	j2 := &innerJob{key: "job2", jitter: time.Minute}
+	if j1.jitterOffset() != j1.jitterOffset() {
+		t.Error("deterministic offset differs between ticks")
+	}
+	if j1.jitterOffset() == j2.jitterOffset() {
+		t.Error("deterministic offsets of different keys are the same")
+	}
+
+	random := &innerJob{key: "job1", jitter: time.Minute, randomJitter: true}
+	for _, j := range []*innerJob{j1, j2, random} {
+		if offset := j.jitterOffset(); offset < 0 || offset >= time.Minute {
+			t.Errorf("offset %v of %v is out of [0, 1m)", offset, j.key)
+		}
+	}
+
+	if offset := (&innerJob{key: "job1"}).jitterOffset(); offset != 0 {
+		t.Errorf("offset without jitter = %v", offset)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	var mu sync.Mutex
+	var tasks []Task
+	var begins []time.Time
+
+	c := NewCron(WithLock(NewMemoryLock(time.Minute)))
+	job := NewJob("job", "* * * * * *", func(ctx context.Context) error {
+		task, _ := TaskFromContext(ctx)
+		mu.Lock()
+		tasks = append(tasks, task)
+		begins = append(begins, time.Now())
+		mu.Unlock()
+		return nil
+	}, WithJitter(500*time.Millisecond))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+	want := c.jobs[0].jitterOffset()
+
+	c.Start()
+	time.Sleep(2500 * time.Millisecond)
+	<-c.Stop().Done()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(tasks) == 0 {
+		t.Fatal("the job has never run")
+	}
+	for i, task := range tasks {
+		if task.JitterOffset != want {
+			t.Errorf("JitterOffset = %v, want %v", task.JitterOffset, want)
+		}
+		if task.PlanAt.Nanosecond() != 0 {
+			t.Errorf("PlanAt %v is not the nominal tick", task.PlanAt)
+		}
+		if begin := begins[i].Sub(task.PlanAt); begin < want {
+			t.Errorf("task began %v after its tick, want at least %v", begin, want)
+		}
+	}
+}
+
+func TestWithJitter_cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntryGetter := mock_dcron.NewMockentryGetter(ctrl)
+	mockEntryGetter.EXPECT().
+		Entry(gomock.Any()).
+		DoAndReturn(func(id cron.EntryID) cron.Entry {
+			now := time.Now()
+			return cron.Entry{ID: id, Next: now.Add(50 * time.Millisecond), Prev: now}
+		}).
+		AnyTimes()
+
+	var after Task
+	j := &innerJob{
+		cron:        NewCron(),
+		entryID:     1,
+		entryGetter: mockEntryGetter,
+		key:         "job",
+		retryTimes:  1,
+		jitter:      time.Hour, // beyond the deadline of the task
+		run: func(ctx context.Context) error {
+			t.Error("the task ran after a cancelled jitter wait")
+			return nil
+		},
+		ctxAfter: func(ctx context.Context, task Task) {
+			after = task
+		},
+	}
+	j.Run()
+
+	if !after.Skipped || after.SkipReason == "" || after.BeginAt != nil || after.JitterOffset != 0 {
+		t.Errorf("task %+v is not skipped", after)
+	}
+	if stats := j.Statistics(); stats.SkippedTask != 1 || stats.FailedRun != 0 || stats.FailedTask != 0 {
+		t.Errorf("statistics %+v", stats)
+	}
+}
+
+func TestCron_AddJobs_jitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		jitter  time.Duration
+		wantErr bool
+	}{
+		{name: "less than interval", spec: "* * * * * *", jitter: 500 * time.Millisecond},
+		{name: "interval", spec: "* * * * * *", jitter: time.Second, wantErr: true},
+		{name: "shortest of intervals", spec: "0 0,1,30 * * * *", jitter: 5 * time.Minute, wantErr: true},
+		{name: "every", spec: "@every 10m", jitter: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCron().AddJobs(NewJob("job", tt.spec, nil, WithJitter(tt.jitter)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Job describes a type which could be added to a cron.
@@ -20,6 +21,15 @@ type Job interface {
 	Options() []JobOption
 }
 
+// ScheduleJob can be implemented by a Job computing its runs itself rather than by a spec,
+// e.g. by a business-day calendar or by a schedule stored in a database.
+// Then its Spec is a description only, which is reported by JobMeta.
+type ScheduleJob interface {
+	Job
+	// Next returns the next run after the given time, or the zero time to stop running.
+	Next(t time.Time) time.Time
+}
+
 type wrappedJob struct {
 	key     string
 	spec    string
@@ -44,6 +54,30 @@ func NewJobWithAutoKey(spec string, run RunFunc, options ...JobOption) Job {
 	return NewJob(funcName(run), spec, run, options...)
 }
 
+type wrappedScheduleJob struct {
+	wrappedJob
+	next func(t time.Time) time.Time
+}
+
+// NewScheduleJob returns a new ScheduleJob running at the times returned by next,
+// the description is returned by Spec.
+func NewScheduleJob(key, description string, next func(t time.Time) time.Time, run RunFunc, options ...JobOption) ScheduleJob {
+	return &wrappedScheduleJob{
+		wrappedJob: wrappedJob{
+			key:     key,
+			spec:    description,
+			run:     run,
+			options: options,
+		},
+		next: next,
+	}
+}
+
+// Next implements ScheduleJob.Next.
+func (j *wrappedScheduleJob) Next(t time.Time) time.Time {
+	return j.next(t)
+}
+
 // Key implements Job.Key.
 func (j *wrappedJob) Key() string {
 	return j.key
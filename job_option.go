@@ -82,13 +82,111 @@ func WithJobSLog(logger SlogLogger) JobOption {
 	}
 }
 
-// WithJobSettings sets the settings.
+// WithSpecs adds specs to the one of the job, so the job runs on any of them
+// under the same key, with the same lock and Statistics. A run planned by several specs at once runs once.
+func WithSpecs(specs ...string) JobOption {
+	return func(job *innerJob) {
+		job.specs = append(job.specs, specs...)
+	}
+}
+
+// WithJobLocation evaluates the spec of the job in the time zone instead of the one of the cron,
+// and reports Task.PlanAt in it. The same can be done by the CRON_TZ= prefix of the spec, but not both.
+func WithJobLocation(loc *time.Location) JobOption {
+	return func(job *innerJob) {
+		job.location = loc
+	}
+}
+
+// WithActiveWindow makes the job fire on its spec only from the offset from midnight to the offset to,
+// e.g. from 8*time.Hour to 20*time.Hour, and only on the weekdays if any are given.
+func WithActiveWindow(from, to time.Duration, weekdays ...time.Weekday) JobOption {
+	return func(job *innerJob) {
+		job.window.From = from
+		job.window.To = to
+		job.window.Weekdays = weekdays
+	}
+}
+
+// WithStartAt makes the job fire on its spec only from the moment on.
+func WithStartAt(startAt time.Time) JobOption {
+	return func(job *innerJob) {
+		job.window.StartAt = startAt
+	}
+}
+
+// WithEndAt makes the job fire on its spec only before the moment, after which the job unregisters itself.
+func WithEndAt(endAt time.Time) JobOption {
+	return func(job *innerJob) {
+		job.window.EndAt = endAt
+	}
+}
+
+// WithJitter delays the start of every task by an offset less than max, spreading the load of jobs with the same spec.
+// The offset is derived from the job key, so it is the same on every tick and on every instance.
+// The lock is still taken at the planned time, and max should be less than the interval between the runs.
+func WithJitter(max time.Duration) JobOption {
+	return func(job *innerJob) {
+		job.jitter = max
+		job.randomJitter = false
+	}
+}
+
+// WithRandomJitter delays the start of every task by a random offset less than max, see WithJitter.
+func WithRandomJitter(max time.Duration) JobOption {
+	return func(job *innerJob) {
+		job.jitter = max
+		job.randomJitter = true
+	}
+}
+
+// WithJobIntervalAnchor makes the "@every" specs of the job tick at the anchor plus multiples of the interval,
+// overriding the anchor of the cron set by WithIntervalAnchor.
+// The job should have an "@every" spec and should not be a ScheduleJob.
+func WithJobIntervalAnchor(anchor time.Time) JobOption {
+	return func(job *innerJob) {
+		job.anchor = &anchor
+	}
+}
+
+// WithJobCalendar excludes the planned runs of the job by the calendar,
+// in addition to the calendar of the cron set by WithCalendar.
+func WithJobCalendar(calendar Calendar) JobOption {
+	return func(job *innerJob) {
+		job.calendar = calendar
+	}
+}
+
+// WithFailover makes the instances which have not taken the lock watch its holder during the tick,
+// and take the task over if the holder has not heartbeated for staleAfter, e.g. because it has crashed.
+// The holder heartbeats every heartbeatInterval, which should be several times less than staleAfter.
+// The Lock has to implement HeartbeatLock.
+func WithFailover(heartbeatInterval, staleAfter time.Duration) JobOption {
+	return func(job *innerJob) {
+		job.heartbeatInterval = heartbeatInterval
+		job.staleAfter = staleAfter
+	}
+}
+
+// WithJobSettings sets the settings passed to the Lock as is.
+// It is a single slot, so prefer WithSetting when several plugins need their settings.
 func WithJobSettings(settings any) JobOption {
 	return func(job *innerJob) {
 		job.settings = settings
 	}
 }
 
+// WithSetting sets the typed setting of the job under the key,
+// which can be read back by the key from JobMeta.
+func WithSetting[T any](key *SettingKey[T], value T) JobOption {
+	return func(job *innerJob) {
+		if job.keyedSettings == nil {
+			job.keyedSettings = map[any]any{}
+		}
+		job.keyedSettings[key] = value
+	}
+}
+
 // WithDeriveContext specifies how to derive a new context for the entire job execution, including
 // before/after hooks, Run, and retry logic. The returned context must derive from the provided ctx
 // to preserve the deadline, cancellation signal, and the embedded Task value (accessible via TaskFromContext).
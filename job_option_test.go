@@ -3,6 +3,7 @@ package dcron
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -266,3 +267,102 @@ func TestWithDeriveContext(t *testing.T) {
 		})
 	}
 }
+
+func TestWithJobLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name    string
+		spec    string
+		options []JobOption
+	}{
+		{
+			name:    "option",
+			spec:    "0 0 9 * * *",
+			options: []JobOption{WithJobLocation(newYork)},
+		},
+		{
+			name: "spec prefix",
+			spec: "CRON_TZ=America/New_York 0 0 9 * * *",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithLocation(time.UTC))
+			if err := c.AddJobs(NewJob("job", tt.spec, nil, tt.options...)); err != nil {
+				t.Fatal(err)
+			}
+			j := c.jobs[0]
+			if j.location.String() != newYork.String() {
+				t.Fatalf("location = %v, want %v", j.location, newYork)
+			}
+
+			// daylight saving time starts on 2024-03-10, 9 AM stays 9 AM in New York
+			schedule := c.cron.Entry(j.entryID).Schedule
+			next := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+			for _, want := range []string{"2024-03-08T14:00:00Z", "2024-03-09T14:00:00Z", "2024-03-10T13:00:00Z", "2024-03-11T13:00:00Z"} {
+				next = schedule.Next(next)
+				if got := next.UTC().Format(time.RFC3339); got != want {
+					t.Errorf("Next() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithJobLocation_planAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var planAt time.Time
+	c := NewCron(WithLocation(time.UTC))
+	job := NewJob("job", "* * * * * *", func(ctx context.Context) error {
+		task, _ := TaskFromContext(ctx)
+		planAt = task.PlanAt
+		return nil
+	}, WithJobLocation(tokyo))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+	time.Sleep(1500 * time.Millisecond)
+	<-c.Stop().Done()
+
+	if planAt.IsZero() {
+		t.Fatal("the job has never run")
+	}
+	if planAt.Location() != tokyo {
+		t.Errorf("PlanAt location = %v, want %v", planAt.Location(), tokyo)
+	}
+}
+
+func TestWithSpecs(t *testing.T) {
+	c := NewCron(WithLocation(time.UTC))
+	job := NewJob("job", "0 0 9 * * MON-FRI", nil, WithSpecs("0 0 12 * * SAT,SUN", "0 0 9 * * MON"))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+	j := c.jobs[0]
+	if got := j.Specs(); !reflect.DeepEqual(got, []string{"0 0 9 * * MON-FRI", "0 0 12 * * SAT,SUN", "0 0 9 * * MON"}) {
+		t.Errorf("Specs() = %v", got)
+	}
+
+	schedule := c.cron.Entry(j.entryID).Schedule
+	next := time.Date(2024, 1, 12, 10, 0, 0, 0, time.UTC) // Friday
+	for _, want := range []string{"2024-01-13T12:00:00Z", "2024-01-14T12:00:00Z", "2024-01-15T09:00:00Z", "2024-01-16T09:00:00Z"} {
+		next = schedule.Next(next)
+		if got := next.Format(time.RFC3339); got != want {
+			t.Errorf("Next() = %v, want %v", got, want)
+		}
+	}
+
+	if err := NewCron().AddJobs(NewJob("job", "* * * * * *", nil, WithSpecs("bad spec"))); err == nil {
+		t.Error("AddJobs() with a bad spec succeeded")
+	}
+}
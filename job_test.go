@@ -3,7 +3,9 @@ package dcron
 import (
 	"context"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewJob(t *testing.T) {
@@ -280,3 +282,49 @@ type Foo struct {
 func (Foo) Method(_ context.Context) error {
 	return nil
 }
+
+func TestNewScheduleJob(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var mu sync.Mutex
+	var runs int
+	var locations []*time.Location
+	next := func(t time.Time) time.Time {
+		mu.Lock()
+		locations = append(locations, t.Location())
+		mu.Unlock()
+		return t.Truncate(500 * time.Millisecond).Add(500 * time.Millisecond)
+	}
+
+	c := NewCron(WithLocation(time.UTC))
+	job := NewScheduleJob("job", "every half a second", next, func(ctx context.Context) error {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		return nil
+	}, WithJobLocation(tokyo))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Jobs()[0].Spec(); got != "every half a second" {
+		t.Errorf("Spec() = %q", got)
+	}
+
+	c.Start()
+	time.Sleep(1600 * time.Millisecond)
+	<-c.Stop().Done()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if runs < 2 {
+		t.Errorf("the job ran %v times", runs)
+	}
+	for _, loc := range locations {
+		if loc != tokyo {
+			t.Errorf("Next() got time in %v, want %v", loc, tokyo)
+		}
+	}
+}
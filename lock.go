@@ -1,6 +1,10 @@
 package dcron
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 //go:generate go get go.uber.org/mock/mockgen@v0.6.0
 //go:generate go run go.uber.org/mock/mockgen@v0.6.0 -source=lock.go -destination mock_dcron/lock.go
@@ -17,5 +21,30 @@ type Lock interface {
 
 	// Unlock removes the key/value,
 	// or does nothing.
+	// The ctx is the task context, which may already be cancelled or past its deadline,
+	// so implementations talking to a server should detach from it, e.g. by context.WithoutCancel.
 	Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any)
 }
+
+// FallibleLock can be implemented by a Lock to tell backend errors apart from a key held by another instance.
+type FallibleLock interface {
+	// TryLock does the same as Lock, but returns an error if the backend failed,
+	// so it is unknown whether the key is held.
+	TryLock(ctx context.Context, jobSetting any, key, value string) (bool, any, error)
+}
+
+// ErrLockSettings is wrapped by the errors of a FallibleLock caused by the job settings rather than by the backend,
+// e.g. a missing lock TTL. FallbackLock reports them instead of falling back, since the backend is not to blame.
+var ErrLockSettings = errors.New("bad lock settings")
+
+// HeartbeatLock can be implemented by a Lock keeping a heartbeat in the lock record,
+// so peers can take over a task whose holder has crashed, see WithFailover.
+type HeartbeatLock interface {
+	// Heartbeat refreshes the heartbeat of a key taken by Lock or TakeOver,
+	// it returns false if the key is not held with the lockValue anymore.
+	Heartbeat(ctx context.Context, jobSetting any, key, value string, lockValue any) bool
+
+	// TakeOver takes the key if its heartbeat is older than staleAfter.
+	// It returns held false if the key has been released or expired, so there is nothing to take over.
+	TakeOver(ctx context.Context, jobSetting any, key, value string, staleAfter time.Duration) (locked bool, lockValue any, held bool)
+}
@@ -0,0 +1,9 @@
+package dcron
+
+// LockValidator can be implemented by a Lock to check the job settings once, when the job is added to a Cron,
+// rather than failing every tick.
+// It lives apart from lock.go, which mocks are generated from, since its signature refers to JobMeta.
+type LockValidator interface {
+	// ValidateJob returns an error if the Lock could not work with the job and its settings.
+	ValidateJob(job JobMeta, jobSetting any) error
+}
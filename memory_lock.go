@@ -0,0 +1,126 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLock is a thread-safe in-memory Lock.
+// It is useful for tests simulating a cluster by several Cron instances sharing one MemoryLock,
+// and for a single process running several Cron instances.
+type MemoryLock struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	seq     uint64
+	entries map[string]memoryLockEntry
+}
+
+type memoryLockEntry struct {
+	owner       string
+	token       uint64
+	expiresAt   time.Time
+	heartbeatAt time.Time
+}
+
+// MemoryLockTTL is the job setting of the MemoryLock TTL.
+var MemoryLockTTL = NewSettingKey[time.Duration]("dcron.MemoryLockTTL")
+
+// WithMemoryLockTTL sets the TTL of the job keys in a MemoryLock, overriding the one of NewMemoryLock.
+func WithMemoryLockTTL(duration time.Duration) JobOption {
+	return WithSetting(MemoryLockTTL, duration)
+}
+
+// NewMemoryLock returns a MemoryLock keeping keys for ttl,
+// unless the job sets WithMemoryLockTTL, or its job settings is a time.Duration, which is used instead.
+func NewMemoryLock(ttl time.Duration) *MemoryLock {
+	return &MemoryLock{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]memoryLockEntry{},
+	}
+}
+
+// Lock implements Lock.Lock.
+func (l *MemoryLock) Lock(ctx context.Context, jobSetting any, key, value string) (bool, any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if e, ok := l.entries[key]; ok && now.Before(e.expiresAt) {
+		return false, nil
+	}
+	return true, l.take(ctx, jobSetting, key, value, now)
+}
+
+// take stores the key held by value until the TTL and returns its token, l.mu must be held.
+func (l *MemoryLock) take(ctx context.Context, jobSetting any, key, value string, now time.Time) uint64 {
+	task, _ := TaskFromContext(ctx)
+	ttl, _ := ResolveLockTTL(task.Job, MemoryLockTTL, jobSetting, l.ttl)
+	if ttl <= 0 {
+		ttl = l.ttl
+	}
+
+	l.seq++
+	l.entries[key] = memoryLockEntry{
+		owner:       value,
+		token:       l.seq,
+		expiresAt:   now.Add(ttl),
+		heartbeatAt: now,
+	}
+	return l.seq
+}
+
+// Unlock implements Lock.Unlock.
+// The key is removed only if it is still held by the same owner and the same Lock call.
+func (l *MemoryLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok && e.owner == value && e.token == lockValue {
+		delete(l.entries, key)
+	}
+}
+
+// Heartbeat implements HeartbeatLock.Heartbeat.
+func (l *MemoryLock) Heartbeat(ctx context.Context, jobSetting any, key, value string, lockValue any) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	e, ok := l.entries[key]
+	if !ok || e.owner != value || e.token != lockValue || !now.Before(e.expiresAt) {
+		return false
+	}
+	e.heartbeatAt = now
+	l.entries[key] = e
+	return true
+}
+
+// TakeOver implements HeartbeatLock.TakeOver.
+func (l *MemoryLock) TakeOver(ctx context.Context, jobSetting any, key, value string, staleAfter time.Duration) (bool, any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	e, ok := l.entries[key]
+	if !ok || !now.Before(e.expiresAt) {
+		return false, nil, false
+	}
+	if now.Sub(e.heartbeatAt) < staleAfter {
+		return false, nil, true
+	}
+	return true, l.take(ctx, jobSetting, key, value, now), true
+}
+
+// Owner returns the owner holding the key, or false if the key is not held.
+func (l *MemoryLock) Owner(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok && l.now().Before(e.expiresAt) {
+		return e.owner, true
+	}
+	return "", false
+}
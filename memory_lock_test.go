@@ -0,0 +1,215 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryLock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		steps func(l *MemoryLock) bool
+		want  bool
+	}{
+		{
+			name: "free",
+			steps: func(l *MemoryLock) bool {
+				ok, _ := l.Lock(ctx, nil, "job", "host1")
+				return ok
+			},
+			want: true,
+		},
+		{
+			name: "held",
+			steps: func(l *MemoryLock) bool {
+				l.Lock(ctx, nil, "job", "host1")
+				ok, _ := l.Lock(ctx, nil, "job", "host2")
+				return ok
+			},
+			want: false,
+		},
+		{
+			name: "expired",
+			steps: func(l *MemoryLock) bool {
+				l.Lock(ctx, nil, "job", "host1")
+				now = now.Add(time.Minute)
+				ok, _ := l.Lock(ctx, nil, "job", "host2")
+				return ok
+			},
+			want: true,
+		},
+		{
+			name: "ttl from job settings",
+			steps: func(l *MemoryLock) bool {
+				l.Lock(ctx, time.Hour, "job", "host1")
+				now = now.Add(time.Minute)
+				ok, _ := l.Lock(ctx, nil, "job", "host2")
+				return ok
+			},
+			want: false,
+		},
+		{
+			name: "ttl from typed setting",
+			steps: func(l *MemoryLock) bool {
+				task := Task{Job: &innerJob{keyedSettings: map[any]any{MemoryLockTTL: time.Hour}}}
+				l.Lock(context.WithValue(ctx, keyContextTask, task), time.Millisecond, "job", "host1")
+				now = now.Add(time.Minute)
+				ok, _ := l.Lock(ctx, nil, "job", "host2")
+				return ok
+			},
+			want: false,
+		},
+		{
+			name: "unlocked",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				l.Unlock(ctx, nil, "job", "host1", v)
+				ok, _ := l.Lock(ctx, nil, "job", "host2")
+				return ok
+			},
+			want: true,
+		},
+		{
+			name: "unlocked by another owner",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				l.Unlock(ctx, nil, "job", "host2", v)
+				owner, _ := l.Owner("job")
+				return owner == "host1"
+			},
+			want: true,
+		},
+		{
+			name: "stale unlock",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				now = now.Add(time.Minute)
+				l.Lock(ctx, nil, "job", "host1")
+				l.Unlock(ctx, nil, "job", "host1", v)
+				_, ok := l.Owner("job")
+				return ok
+			},
+			want: true,
+		},
+		{
+			name: "take over stale",
+			steps: func(l *MemoryLock) bool {
+				l.Lock(ctx, nil, "job", "host1")
+				now = now.Add(100 * time.Millisecond)
+				ok, _, _ := l.TakeOver(ctx, nil, "job", "host2", 50*time.Millisecond)
+				owner, _ := l.Owner("job")
+				return ok && owner == "host2"
+			},
+			want: true,
+		},
+		{
+			name: "take over heartbeating",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				now = now.Add(100 * time.Millisecond)
+				l.Heartbeat(ctx, nil, "job", "host1", v)
+				ok, _, held := l.TakeOver(ctx, nil, "job", "host2", 50*time.Millisecond)
+				return !ok && held
+			},
+			want: true,
+		},
+		{
+			name: "take over released",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				l.Unlock(ctx, nil, "job", "host1", v)
+				ok, _, held := l.TakeOver(ctx, nil, "job", "host2", 0)
+				return ok || held
+			},
+			want: false,
+		},
+		{
+			name: "heartbeat after take over",
+			steps: func(l *MemoryLock) bool {
+				_, v := l.Lock(ctx, nil, "job", "host1")
+				l.TakeOver(ctx, nil, "job", "host2", 0)
+				return l.Heartbeat(ctx, nil, "job", "host1", v)
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewMemoryLock(time.Second)
+			l.now = func() time.Time { return now }
+			if got := tt.steps(l); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryLock_cluster(t *testing.T) {
+	const interval = 200 * time.Millisecond
+	const ticks = 5
+	lock := NewMemoryLock(time.Minute)
+
+	var mu sync.Mutex
+	runs := map[time.Time]int{}
+	finished := make(chan Task, 100)
+
+	var crons []*Cron
+	for _, hostname := range []string{"host1", "host2", "host3"} {
+		c := NewCron(WithLock(lock), WithHostname(hostname))
+		job := NewScheduleJob("job", "every 200ms", func(t time.Time) time.Time {
+			// the same ticks on every instance
+			return t.Truncate(interval).Add(interval)
+		}, func(ctx context.Context) error {
+			task, _ := TaskFromContext(ctx)
+			mu.Lock()
+			runs[task.PlanAt]++
+			mu.Unlock()
+			time.Sleep(interval / 2) // keeps the lock while the others are trying
+			return nil
+		}, WithAfterContextFunc(func(ctx context.Context, task Task) {
+			finished <- task
+		}))
+		if err := c.AddJobs(job); err != nil {
+			t.Fatal(err)
+		}
+		crons = append(crons, c)
+	}
+
+	for _, c := range crons {
+		c.Start()
+	}
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < ticks*len(crons); i++ {
+		select {
+		case <-finished:
+		case <-timeout:
+			t.Fatalf("only %d tasks have finished", i)
+		}
+	}
+	for _, c := range crons {
+		<-c.Stop().Done()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(runs) == 0 {
+		t.Fatal("the job has never run")
+	}
+	for planAt, n := range runs {
+		if n != 1 {
+			t.Errorf("task planned at %v ran %v times", planAt, n)
+		}
+	}
+	stats := Statistics{}
+	for _, c := range crons {
+		stats = stats.Add(c.Statistics())
+	}
+	if stats.PassedTask+stats.MissedTask != stats.TotalTask {
+		t.Errorf("statistics %+v: passed and missed tasks do not sum up to total", stats)
+	}
+}
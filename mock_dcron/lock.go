@@ -12,6 +12,7 @@ package mock_dcron
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -66,3 +67,97 @@ func (mr *MockLockMockRecorder) Unlock(ctx, jobSetting, key, value, lockValue an
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLock)(nil).Unlock), ctx, jobSetting, key, value, lockValue)
 }
+
+// MockFallibleLock is a mock of FallibleLock interface.
+type MockFallibleLock struct {
+	ctrl     *gomock.Controller
+	recorder *MockFallibleLockMockRecorder
+	isgomock struct{}
+}
+
+// MockFallibleLockMockRecorder is the mock recorder for MockFallibleLock.
+type MockFallibleLockMockRecorder struct {
+	mock *MockFallibleLock
+}
+
+// NewMockFallibleLock creates a new mock instance.
+func NewMockFallibleLock(ctrl *gomock.Controller) *MockFallibleLock {
+	mock := &MockFallibleLock{ctrl: ctrl}
+	mock.recorder = &MockFallibleLockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFallibleLock) EXPECT() *MockFallibleLockMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockFallibleLock) TryLock(ctx context.Context, jobSetting any, key, value string) (bool, any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, jobSetting, key, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(any)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockFallibleLockMockRecorder) TryLock(ctx, jobSetting, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockFallibleLock)(nil).TryLock), ctx, jobSetting, key, value)
+}
+
+// MockHeartbeatLock is a mock of HeartbeatLock interface.
+type MockHeartbeatLock struct {
+	ctrl     *gomock.Controller
+	recorder *MockHeartbeatLockMockRecorder
+	isgomock struct{}
+}
+
+// MockHeartbeatLockMockRecorder is the mock recorder for MockHeartbeatLock.
+type MockHeartbeatLockMockRecorder struct {
+	mock *MockHeartbeatLock
+}
+
+// NewMockHeartbeatLock creates a new mock instance.
+func NewMockHeartbeatLock(ctrl *gomock.Controller) *MockHeartbeatLock {
+	mock := &MockHeartbeatLock{ctrl: ctrl}
+	mock.recorder = &MockHeartbeatLockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHeartbeatLock) EXPECT() *MockHeartbeatLockMockRecorder {
+	return m.recorder
+}
+
+// Heartbeat mocks base method.
+func (m *MockHeartbeatLock) Heartbeat(ctx context.Context, jobSetting any, key, value string, lockValue any) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, jobSetting, key, value, lockValue)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockHeartbeatLockMockRecorder) Heartbeat(ctx, jobSetting, key, value, lockValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockHeartbeatLock)(nil).Heartbeat), ctx, jobSetting, key, value, lockValue)
+}
+
+// TakeOver mocks base method.
+func (m *MockHeartbeatLock) TakeOver(ctx context.Context, jobSetting any, key, value string, staleAfter time.Duration) (bool, any, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeOver", ctx, jobSetting, key, value, staleAfter)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(any)
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
+}
+
+// TakeOver indicates an expected call of TakeOver.
+func (mr *MockHeartbeatLockMockRecorder) TakeOver(ctx, jobSetting, key, value, staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeOver", reflect.TypeOf((*MockHeartbeatLock)(nil).TakeOver), ctx, jobSetting, key, value, staleAfter)
+}
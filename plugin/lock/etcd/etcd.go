@@ -0,0 +1,161 @@
+package etcd
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/nkonev/dcron"
+)
+
+// DefaultLeaseTTL is the lease TTL unless WithLeaseTTL is used.
+const DefaultLeaseTTL = 10 * time.Second
+
+// EtcdLock implements dcron.Lock via keys bound to a lease.
+// The lease is kept alive while the task runs, so the key outlives long tasks,
+// but disappears within the lease TTL if the process dies.
+type EtcdLock struct {
+	client     *clientv3.Client
+	leaseTTL   time.Duration
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+// Lease is the lock value of a taken lock.
+type Lease struct {
+	// ID is the lease the key is bound to.
+	ID clientv3.LeaseID
+	// Revision is the revision the key was created at,
+	// it grows monotonically, so it may be used as a fencing token.
+	Revision int64
+
+	stopKeepAlive context.CancelFunc
+}
+
+func WithLock(client *clientv3.Client, options ...EtcdLockOption) dcron.CronOption {
+	return dcron.WithLock(NewEtcdLock(client, options...))
+}
+
+func (m *EtcdLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *EtcdLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	ttl := int64(m.leaseTTL / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	lease, err := m.client.Grant(ctx, ttl)
+	if err != nil {
+		m.logError(ctx, "unable to grant etcd lease", key, err)
+		return false, nil, err
+	}
+
+	resp, err := m.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, value, clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil || !resp.Succeeded {
+		if err != nil {
+			m.logError(ctx, "unable to take etcd lock", key, err)
+		}
+		m.revoke(ctx, key, lease.ID)
+		return false, nil, err
+	}
+
+	// keep-alive should last until Unlock rather than until the task deadline
+	keepAliveCtx, stopKeepAlive := context.WithCancel(context.WithoutCancel(ctx))
+	keepAlive, err := m.client.KeepAlive(keepAliveCtx, lease.ID)
+	if err != nil {
+		stopKeepAlive()
+		m.logError(ctx, "unable to keep etcd lease alive", key, err)
+		m.revoke(ctx, key, lease.ID)
+		return false, nil, err
+	}
+	go func() {
+		for range keepAlive {
+		}
+	}()
+
+	return true, &Lease{
+		ID:            lease.ID,
+		Revision:      resp.Header.Revision,
+		stopKeepAlive: stopKeepAlive,
+	}, nil
+}
+
+func (m *EtcdLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	lease, ok := lockValue.(*Lease)
+	if !ok {
+		return
+	}
+	lease.stopKeepAlive()
+
+	ctx = context.WithoutCancel(ctx)
+
+	// the key is deleted only if it is still the one created by this lock,
+	// and revoking the lease cleans up anything bound to it
+	_, err := m.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", lease.Revision)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		m.logError(ctx, "unable to release etcd lock", key, err)
+	}
+	m.revoke(ctx, key, lease.ID)
+}
+
+func (m *EtcdLock) revoke(ctx context.Context, key string, id clientv3.LeaseID) {
+	if _, err := m.client.Revoke(context.WithoutCancel(ctx), id); err != nil {
+		m.logError(ctx, "unable to revoke etcd lease", key, err)
+	}
+}
+
+func (m *EtcdLock) logError(ctx context.Context, msg, key string, err error) {
+	if m.logger != nil {
+		m.logger.Errorf(msg+" %v: %v", key, err)
+	}
+	if m.slogLogger != nil {
+		m.slogLogger.ErrorContext(ctx, msg, dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+	}
+}
+
+func NewEtcdLock(client *clientv3.Client, options ...EtcdLockOption) *EtcdLock {
+	ret := &EtcdLock{
+		client:   client,
+		leaseTTL: DefaultLeaseTTL,
+	}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type EtcdLockOption func(el *EtcdLock)
+
+// WithLeaseTTL sets how long the lock survives its holder when the process dies.
+// It is rounded down to seconds, but not less than one second.
+func WithLeaseTTL(ttl time.Duration) EtcdLockOption {
+	return func(el *EtcdLock) {
+		el.leaseTTL = ttl
+	}
+}
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) EtcdLockOption {
+	return func(el *EtcdLock) {
+		el.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) EtcdLockOption {
+	return func(el *EtcdLock) {
+		el.slogLogger = logger
+	}
+}
@@ -0,0 +1,85 @@
+package etcd
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+func newEmbeddedClient(t *testing.T) *clientv3.Client {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL, _ := url.Parse("http://127.0.0.1:0")
+	peerURL, _ := url.Parse("http://127.0.0.1:0")
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("embedded etcd is not ready")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{e.Clients[0].Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestEtcdLock(t *testing.T) {
+	ctx := context.Background()
+	client := newEmbeddedClient(t)
+	lock := NewEtcdLock(client, WithLeaseTTL(time.Second))
+
+	ok, v := lock.Lock(ctx, nil, "job", "host1")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	if lease := v.(*Lease); lease.Revision == 0 {
+		t.Error("Lock() returned zero revision")
+	}
+
+	if ok, _ := lock.Lock(ctx, nil, "job", "host2"); ok {
+		t.Fatal("Lock() of a held key = true, want false")
+	}
+
+	// keep-alive holds the key longer than the lease TTL
+	time.Sleep(2500 * time.Millisecond)
+	if ok, _ := lock.Lock(ctx, nil, "job", "host2"); ok {
+		t.Fatal("Lock() of a kept alive key = true, want false")
+	}
+
+	lock.Unlock(ctx, nil, "job", "host1", v)
+	ok, v2 := lock.Lock(ctx, nil, "job", "host2")
+	if !ok {
+		t.Fatal("Lock() of an unlocked key = false, want true")
+	}
+	if v2.(*Lease).Revision <= v.(*Lease).Revision {
+		t.Errorf("revision %v is not greater than the previous one %v", v2.(*Lease).Revision, v.(*Lease).Revision)
+	}
+
+	// a stale holder must not delete the key of the new one
+	lock.Unlock(ctx, nil, "job", "host1", v)
+	if ok, _ := lock.Lock(ctx, nil, "job", "host3"); ok {
+		t.Fatal("Lock() after a stale Unlock = true, want false")
+	}
+	lock.Unlock(ctx, nil, "job", "host2", v2)
+}
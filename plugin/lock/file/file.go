@@ -0,0 +1,177 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nkonev/dcron"
+)
+
+// guardName is the file serializing stale lock breaking in the directory.
+const guardName = ".dcron.guard"
+
+// FileLock implements dcron.Lock via flock(2) on per-key files in a directory,
+// it gives distributed cron semantics to the processes of a single host.
+// The kernel releases a flock as soon as the holder process dies.
+// A lock still held while the pid written to its file is gone
+// (for example, its descriptor leaked to a child process) is treated as stale and broken.
+type FileLock struct {
+	dir        string
+	pid        int
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+func WithLock(dir string, options ...FileLockOption) dcron.CronOption {
+	return dcron.WithLock(NewFileLock(dir, options...))
+}
+
+func (m *FileLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *FileLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		m.logError(ctx, "unable to create lock directory", key, err)
+		return false, nil, err
+	}
+
+	path := m.path(key)
+	f, locked, err := tryLock(path)
+	if err != nil {
+		m.logError(ctx, "unable to take file lock", key, err)
+		return false, nil, err
+	}
+
+	if !locked {
+		broken, err := m.breakStale(path)
+		if err != nil {
+			m.logError(ctx, "unable to break stale file lock", key, err)
+			return false, nil, err
+		}
+		if !broken {
+			return false, nil, nil
+		}
+		if f, locked, err = tryLock(path); err != nil {
+			m.logError(ctx, "unable to take file lock", key, err)
+			return false, nil, err
+		}
+		if !locked {
+			return false, nil, nil
+		}
+	}
+
+	if err := writeOwner(f, m.pid, value); err != nil {
+		m.logError(ctx, "unable to write file lock owner", key, err)
+		unlock(f)
+		return false, nil, err
+	}
+	return true, f, nil
+}
+
+func (m *FileLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	if f, ok := lockValue.(*os.File); ok {
+		unlock(f)
+	}
+}
+
+// breakStale removes the lock file if its owner process is gone.
+// Removal happens under the directory guard and after another attempt to lock,
+// so a lock taken by a live process in the meantime is never removed.
+func (m *FileLock) breakStale(path string) (bool, error) {
+	pid, err := readOwner(path)
+	if err != nil || pid == 0 || processAlive(pid) {
+		return false, nil
+	}
+
+	guard, err := lockGuard(filepath.Join(m.dir, guardName))
+	if err != nil {
+		return false, err
+	}
+	defer unlock(guard)
+
+	f, locked, err := tryLock(path)
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		// the holder is gone already
+		unlock(f)
+		return true, nil
+	}
+	if current, err := readOwner(path); err != nil || current != pid {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+func writeOwner(f *os.File, pid int, value string) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(fmt.Sprintf("%d\n%s\n", pid, value)), 0)
+	return err
+}
+
+// readOwner returns the pid written to the lock file, or zero if there is none.
+func readOwner(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	line, _, _ := strings.Cut(string(content), "\n")
+	pid, _ := strconv.Atoi(line)
+	return pid, nil
+}
+
+func (m *FileLock) path(key string) string {
+	return filepath.Join(m.dir, url.PathEscape(key)+".lock")
+}
+
+func (m *FileLock) logError(ctx context.Context, msg, key string, err error) {
+	if m.logger != nil {
+		m.logger.Errorf(msg+" %v: %v", key, err)
+	}
+	if m.slogLogger != nil {
+		m.slogLogger.ErrorContext(ctx, msg, dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+	}
+}
+
+func NewFileLock(dir string, options ...FileLockOption) *FileLock {
+	ret := &FileLock{
+		dir: dir,
+		pid: os.Getpid(),
+	}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type FileLockOption func(fl *FileLock)
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) FileLockOption {
+	return func(fl *FileLock) {
+		fl.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) FileLockOption {
+	return func(fl *FileLock) {
+		fl.slogLogger = logger
+	}
+}
@@ -0,0 +1,70 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package file
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestFileLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	lock1 := NewFileLock(dir)
+	lock2 := NewFileLock(dir)
+
+	ok, v := lock1.Lock(ctx, nil, "job/1", "host")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	if ok, _ := lock2.Lock(ctx, nil, "job/1", "host"); ok {
+		t.Fatal("Lock() of a held key = true, want false")
+	}
+	if ok, v := lock2.Lock(ctx, nil, "job/2", "host"); !ok {
+		t.Fatal("Lock() of another key = false, want true")
+	} else {
+		lock2.Unlock(ctx, nil, "job/2", "host", v)
+	}
+
+	lock1.Unlock(ctx, nil, "job/1", "host", v)
+	ok, v = lock2.Lock(ctx, nil, "job/1", "host")
+	if !ok {
+		t.Fatal("Lock() of an unlocked key = false, want true")
+	}
+	lock2.Unlock(ctx, nil, "job/1", "host", v)
+}
+
+func TestFileLock_stale(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// a finished child process gives a pid which is not alive
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip(err)
+	}
+	dead := NewFileLock(dir)
+	dead.pid = cmd.Process.Pid
+
+	ok, v := dead.Lock(ctx, nil, "job", "host")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	defer dead.Unlock(ctx, nil, "job", "host", v)
+
+	live := NewFileLock(dir)
+	ok, v2 := live.Lock(ctx, nil, "job", "host")
+	if !ok {
+		t.Fatal("Lock() of a stale key = false, want true")
+	}
+	defer live.Unlock(ctx, nil, "job", "host", v2)
+
+	if pid, _ := readOwner(live.path("job")); pid != os.Getpid() {
+		t.Errorf("owner pid = %v, want %v", pid, os.Getpid())
+	}
+	if ok, _ := NewFileLock(dir).Lock(ctx, nil, "job", "host"); ok {
+		t.Fatal("Lock() of a broken and retaken key = true, want false")
+	}
+}
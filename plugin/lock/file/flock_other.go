@@ -0,0 +1,24 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package file
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLock(path string) (*os.File, bool, error) {
+	return nil, false, errors.ErrUnsupported
+}
+
+func lockGuard(path string) (*os.File, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func unlock(f *os.File) {
+	_ = f.Close()
+}
+
+func processAlive(pid int) bool {
+	return true
+}
@@ -0,0 +1,9 @@
+module github.com/nkonev/dcron/plugin/lock/file
+
+go 1.23.0
+
+require github.com/nkonev/dcron v1.8.0
+
+require github.com/robfig/cron/v3 v3.0.1 // indirect
+
+replace github.com/nkonev/dcron => ../../..
@@ -0,0 +1,143 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/nkonev/dcron"
+)
+
+// MongoLock implements dcron.Lock via documents {_id: key, owner, expiresAt} in a collection.
+// The unique _id prevents two holders of a key, and an expired document is taken over by the next Lock
+// without waiting for the TTL index, which only cleans the collection up.
+type MongoLock struct {
+	collection *mongoDriver.Collection
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+// LockTTL is the job setting of the lock TTL.
+var LockTTL = dcron.NewSettingKey[time.Duration]("mongo.LockTTL")
+
+func WithLockTTL(duration time.Duration) dcron.JobOption {
+	return dcron.WithSetting(LockTTL, duration)
+}
+
+func WithLock(collection *mongoDriver.Collection, options ...MongoLockOption) dcron.CronOption {
+	return dcron.WithLock(NewMongoLock(collection, options...))
+}
+
+// EnsureIndexes creates the TTL index removing expired locks.
+func (m *MongoLock) EnsureIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateOne(ctx, mongoDriver.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+func (m *MongoLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *MongoLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	task, _ := dcron.TaskFromContext(ctx)
+	duration, ok := dcron.ResolveLockTTL(task.Job, LockTTL, jobSettings, 0)
+	if !ok {
+		if m.logger != nil {
+			m.logger.Errorf("no lock ttl, use WithLockTTL %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "no lock ttl, use WithLockTTL", dcron.SlogKeyTaskName, key)
+		}
+
+		return false, nil, fmt.Errorf("%w: no lock ttl", dcron.ErrLockSettings)
+	}
+
+	if duration == 0 {
+		if m.logger != nil {
+			m.logger.Errorf("bad zero expiration %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "bad zero expiration", dcron.SlogKeyTaskName, key)
+		}
+		return false, nil, fmt.Errorf("%w: zero expiration", dcron.ErrLockSettings)
+	}
+
+	// BSON dates have millisecond precision, the value has to survive the round trip for Unlock
+	now := time.Now().Truncate(time.Millisecond)
+	expiresAt := now.Add(duration)
+
+	// matches only an expired document, so for a held key the upsert turns into a duplicate _id insert
+	_, err := m.collection.UpdateOne(ctx,
+		bson.M{"_id": key, "expiresAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"owner": value, "expiresAt": expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	if mongoDriver.IsDuplicateKeyError(err) {
+		return false, nil, nil
+	}
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to take mongo lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to take mongo lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	return true, expiresAt, nil
+}
+
+func (m *MongoLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	expiresAt, ok := lockValue.(time.Time)
+	if !ok {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	_, err := m.collection.DeleteOne(ctx, bson.M{"_id": key, "owner": value, "expiresAt": expiresAt})
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to release mongo lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to release mongo lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+	}
+}
+
+func NewMongoLock(collection *mongoDriver.Collection, options ...MongoLockOption) *MongoLock {
+	ret := &MongoLock{collection: collection}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type MongoLockOption func(ml *MongoLock)
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) MongoLockOption {
+	return func(ml *MongoLock) {
+		ml.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) MongoLockOption {
+	return func(ml *MongoLock) {
+		ml.slogLogger = logger
+	}
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newCollection connects to the MongoDB of MONGO_URI, e.g. "mongodb://localhost:27017",
+// and returns an empty collection dropped after the test.
+func newCollection(t *testing.T) *mongoDriver.Collection {
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI is not set")
+	}
+	ctx := context.Background()
+	client, err := mongoDriver.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(ctx) })
+
+	collection := client.Database("dcron_test").Collection(t.Name())
+	if err := collection.Drop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = collection.Drop(ctx) })
+	if err := NewMongoLock(collection).EnsureIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return collection
+}
+
+func TestMongoLock(t *testing.T) {
+	ctx := context.Background()
+	lock := NewMongoLock(newCollection(t))
+
+	if ok, _ := lock.Lock(ctx, nil, "a job", "host1"); ok {
+		t.Fatal("Lock() without ttl = true, want false")
+	}
+
+	ok, v := lock.Lock(ctx, time.Minute, "a job", "host1")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	if ok, _ := lock.Lock(ctx, time.Minute, "a job", "host2"); ok {
+		t.Fatal("Lock() of a held key = true, want false")
+	}
+	if ok, v := lock.Lock(ctx, time.Minute, "another job", "host2"); !ok {
+		t.Fatal("Lock() of another key = false, want true")
+	} else {
+		lock.Unlock(ctx, time.Minute, "another job", "host2", v)
+	}
+
+	// only the owner releases the lock
+	lock.Unlock(ctx, time.Minute, "a job", "host2", v)
+	if ok, _ := lock.Lock(ctx, time.Minute, "a job", "host2"); ok {
+		t.Fatal("Lock() of a key unlocked by another owner = true, want false")
+	}
+
+	lock.Unlock(ctx, time.Minute, "a job", "host1", v)
+	ok, v = lock.Lock(ctx, time.Minute, "a job", "host2")
+	if !ok {
+		t.Fatal("Lock() of an unlocked key = false, want true")
+	}
+	lock.Unlock(ctx, time.Minute, "a job", "host2", v)
+}
+
+func TestMongoLock_expired(t *testing.T) {
+	ctx := context.Background()
+	lock := NewMongoLock(newCollection(t))
+
+	ok, v1 := lock.Lock(ctx, 100*time.Millisecond, "a job", "host1")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	// taken over before the TTL index removes the document
+	ok, v2 := lock.Lock(ctx, time.Minute, "a job", "host2")
+	if !ok {
+		t.Fatal("Lock() of an expired key = false, want true")
+	}
+
+	// the stale holder must not delete the lock of the next one
+	lock.Unlock(ctx, time.Minute, "a job", "host1", v1)
+	if ok, _ := lock.Lock(ctx, time.Minute, "a job", "host3"); ok {
+		t.Fatal("Lock() of a key unlocked by a stale holder = true, want false")
+	}
+	lock.Unlock(ctx, time.Minute, "a job", "host2", v2)
+}
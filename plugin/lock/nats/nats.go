@@ -0,0 +1,140 @@
+package nats
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/nkonev/dcron"
+)
+
+// NatsLock implements dcron.Lock over a JetStream key-value bucket.
+// The bucket must allow per-key TTLs, see CreateKeyValue.
+type NatsLock struct {
+	kv         jetstream.KeyValue
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+// LockTTL is the job setting of the lock TTL.
+var LockTTL = dcron.NewSettingKey[time.Duration]("nats.LockTTL")
+
+func WithLockTTL(duration time.Duration) dcron.JobOption {
+	return dcron.WithSetting(LockTTL, duration)
+}
+
+func WithLock(kv jetstream.KeyValue, options ...NatsLockOption) dcron.CronOption {
+	return dcron.WithLock(NewNatsLock(kv, options...))
+}
+
+// CreateKeyValue creates or updates the bucket with per-key TTLs enabled, as NatsLock needs them.
+func CreateKeyValue(ctx context.Context, js jetstream.JetStream, bucket string) (jetstream.KeyValue, error) {
+	return js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:         bucket,
+		History:        1,
+		LimitMarkerTTL: time.Second,
+	})
+}
+
+func (m *NatsLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *NatsLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	task, _ := dcron.TaskFromContext(ctx)
+	duration, ok := dcron.ResolveLockTTL(task.Job, LockTTL, jobSettings, 0)
+	if !ok {
+		if m.logger != nil {
+			m.logger.Errorf("no lock ttl, use WithLockTTL %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "no lock ttl, use WithLockTTL", dcron.SlogKeyTaskName, key)
+		}
+
+		return false, nil, fmt.Errorf("%w: no lock ttl", dcron.ErrLockSettings)
+	}
+
+	// JetStream does not support TTLs less than a second
+	if duration < time.Second {
+		if m.logger != nil {
+			m.logger.Errorf("bad expiration less than a second %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "bad expiration less than a second", dcron.SlogKeyTaskName, key)
+		}
+		return false, nil, fmt.Errorf("%w: expiration less than a second", dcron.ErrLockSettings)
+	}
+
+	revision, err := m.kv.Create(ctx, encodeKey(key), []byte(value), jetstream.KeyTTL(duration))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return false, nil, nil
+	}
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to take nats lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to take nats lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	return true, revision, nil
+}
+
+func (m *NatsLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	revision, ok := lockValue.(uint64)
+	if !ok {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	// the key is deleted only if nobody has taken it over after expiration
+	err := m.kv.Delete(ctx, encodeKey(key), jetstream.LastRevision(revision))
+	if err != nil && !errors.Is(err, jetstream.ErrKeyExists) {
+		if m.logger != nil {
+			m.logger.Errorf("unable to release nats lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to release nats lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+	}
+}
+
+// encodeKey makes any job key a valid bucket key, which allows only [-/_=.a-zA-Z0-9].
+func encodeKey(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func NewNatsLock(kv jetstream.KeyValue, options ...NatsLockOption) *NatsLock {
+	ret := &NatsLock{kv: kv}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type NatsLockOption func(nl *NatsLock)
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) NatsLockOption {
+	return func(nl *NatsLock) {
+		nl.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) NatsLockOption {
+	return func(nl *NatsLock) {
+		nl.slogLogger = logger
+	}
+}
@@ -0,0 +1,85 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsGo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func newEmbeddedKeyValue(t *testing.T) jetstream.KeyValue {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatal("embedded nats-server is not ready")
+	}
+
+	nc, err := natsGo.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, err := CreateKeyValue(context.Background(), js, "dcron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kv
+}
+
+func TestNatsLock(t *testing.T) {
+	ctx := context.Background()
+	lock := NewNatsLock(newEmbeddedKeyValue(t))
+
+	if ok, _ := lock.Lock(ctx, nil, "a job", "host1"); ok {
+		t.Fatal("Lock() without ttl = true, want false")
+	}
+
+	ok, v := lock.Lock(ctx, time.Second, "a job", "host1")
+	if !ok {
+		t.Fatal("Lock() of a free key = false, want true")
+	}
+	if ok, _ := lock.Lock(ctx, time.Second, "a job", "host2"); ok {
+		t.Fatal("Lock() of a held key = true, want false")
+	}
+	if ok, v := lock.Lock(ctx, time.Second, "another job", "host2"); !ok {
+		t.Fatal("Lock() of another key = false, want true")
+	} else {
+		lock.Unlock(ctx, time.Second, "another job", "host2", v)
+	}
+
+	lock.Unlock(ctx, time.Second, "a job", "host1", v)
+	ok, v = lock.Lock(ctx, time.Second, "a job", "host2")
+	if !ok {
+		t.Fatal("Lock() of an unlocked key = false, want true")
+	}
+
+	// the key expires by its own ttl and the stale holder must not delete the next one
+	time.Sleep(2500 * time.Millisecond)
+	ok, v2 := lock.Lock(ctx, time.Minute, "a job", "host3")
+	if !ok {
+		t.Fatal("Lock() of an expired key = false, want true")
+	}
+	lock.Unlock(ctx, time.Second, "a job", "host2", v)
+	if ok, _ := lock.Lock(ctx, time.Minute, "a job", "host1"); ok {
+		t.Fatal("Lock() after a stale Unlock = true, want false")
+	}
+	lock.Unlock(ctx, time.Minute, "a job", "host3", v2)
+}
@@ -0,0 +1,12 @@
+module github.com/nkonev/dcron/plugin/lock/postgres
+
+go 1.23.0
+
+require (
+	github.com/lib/pq v1.10.9
+	github.com/nkonev/dcron v1.8.0
+)
+
+require github.com/robfig/cron/v3 v3.0.1 // indirect
+
+replace github.com/nkonev/dcron => ../../..
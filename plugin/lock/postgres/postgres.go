@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"hash/fnv"
+
+	"github.com/nkonev/dcron"
+)
+
+// PostgresLock implements dcron.Lock via session level advisory locks.
+// Every taken lock holds a dedicated connection until Unlock,
+// so the lock is released by the server as soon as the process dies.
+type PostgresLock struct {
+	db         *sql.DB
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+func WithLock(db *sql.DB, options ...PostgresLockOption) dcron.CronOption {
+	return dcron.WithLock(NewPostgresLock(db, options...))
+}
+
+func (m *PostgresLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *PostgresLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to get postgres connection %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to get postgres connection", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockID(key)).Scan(&locked); err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to take postgres advisory lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to take postgres advisory lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		discard(conn)
+		return false, nil, err
+	}
+
+	if !locked {
+		_ = conn.Close()
+		return false, nil, nil
+	}
+	return true, conn, nil
+}
+
+func (m *PostgresLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	conn, ok := lockValue.(*sql.Conn)
+	if !ok {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	var unlocked bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", lockID(key)).Scan(&unlocked)
+	if err == nil && !unlocked {
+		err = errors.New("the lock is not held by the session")
+	}
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to release postgres advisory lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to release postgres advisory lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		// closing the session is the only way left to release the lock
+		discard(conn)
+		return
+	}
+	_ = conn.Close()
+}
+
+// discard closes the physical connection instead of returning it to the pool,
+// which ends the session together with all the advisory locks it holds.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(driverConn any) error {
+		return driver.ErrBadConn
+	})
+	_ = conn.Close()
+}
+
+// lockID maps the job key onto the bigint key space of advisory locks.
+func lockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+func NewPostgresLock(db *sql.DB, options ...PostgresLockOption) *PostgresLock {
+	ret := &PostgresLock{db: db}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type PostgresLockOption func(pl *PostgresLock)
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) PostgresLockOption {
+	return func(pl *PostgresLock) {
+		pl.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) PostgresLockOption {
+	return func(pl *PostgresLock) {
+		pl.slogLogger = logger
+	}
+}
@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	redisV9 "github.com/redis/go-redis/v9"
+
+	"github.com/nkonev/dcron"
+)
+
+// The heartbeat is the Redis server time in milliseconds, so the clocks of the instances do not matter.
+const redisNowMillis = `
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+`
+
+// heartbeatScript refreshes the heartbeat only if the key is still held by the owner.
+var heartbeatScript = redisV9.NewScript(redisNowMillis + `
+if redis.call("get", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+local ttl = redis.call("pttl", KEYS[1])
+if ttl > 0 then
+	redis.call("set", KEYS[2], now, "px", ttl)
+else
+	redis.call("set", KEYS[2], now)
+end
+return 1`)
+
+// takeOverScript returns 0 if the key is released, 1 if its holder is alive and 2 if the key has been taken over.
+// A key without a heartbeat gets it, so its holder is considered dead only after staleAfter.
+var takeOverScript = redisV9.NewScript(redisNowMillis + `
+if redis.call("exists", KEYS[1]) == 0 then
+	return 0
+end
+local heartbeat = redis.call("get", KEYS[2])
+if not heartbeat then
+	local ttl = redis.call("pttl", KEYS[1])
+	if ttl > 0 then
+		redis.call("set", KEYS[2], now, "px", ttl)
+	else
+		redis.call("set", KEYS[2], now)
+	end
+	return 1
+end
+if now - tonumber(heartbeat) < tonumber(ARGV[2]) then
+	return 1
+end
+redis.call("set", KEYS[1], ARGV[1], "px", ARGV[3])
+redis.call("set", KEYS[2], now, "px", ARGV[3])
+return 2`)
+
+// Heartbeat implements dcron.HeartbeatLock.
+func (m *RedisLock) Heartbeat(ctx context.Context, jobSettings any, key, value string, lockValue any) bool {
+	ok, err := heartbeatScript.Run(ctx, m.client, []string{m.redisKey(key), m.heartbeatKey(key)}, value).Bool()
+	if err != nil {
+		m.logError(ctx, "unable to heartbeat redis lock", key, err)
+	}
+	return ok
+}
+
+// TakeOver implements dcron.HeartbeatLock.
+func (m *RedisLock) TakeOver(ctx context.Context, jobSettings any, key, value string, staleAfter time.Duration) (bool, any, bool) {
+	duration, err := m.ttl(ctx, jobSettings)
+	if err != nil {
+		m.logError(ctx, "unable to get lock ttl", key, err)
+		return false, nil, false
+	}
+
+	state, err := takeOverScript.Run(ctx, m.client, []string{m.redisKey(key), m.heartbeatKey(key)},
+		value, staleAfter.Milliseconds(), duration.Milliseconds()).Int()
+	if err != nil {
+		m.logError(ctx, "unable to take over redis lock", key, err)
+		// the holder may be alive, the next attempt tells
+		return false, nil, true
+	}
+	return state == 2, nil, state != 0
+}
+
+// heartbeatKey returns the key of the heartbeat stored next to the lock key,
+// so that both are in the same Redis Cluster slot.
+func (m *RedisLock) heartbeatKey(key string) string {
+	redisKey := m.redisKey(key)
+	if hashTag(redisKey) != "" {
+		return redisKey + ":heartbeat"
+	}
+	if !strings.Contains(redisKey, "}") {
+		return "{" + redisKey + "}:heartbeat"
+	}
+	// the whole key is hashed, but it cannot be a hash tag itself, so a tag of the same slot is used
+	return "{" + slotTag(keySlot(redisKey)) + "}" + redisKey + ":heartbeat"
+}
+
+// hashTag returns the part of the key Redis Cluster hashes instead of the whole key:
+// the substring between the first "{" and the next "}", if it is not empty.
+func hashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return ""
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return ""
+	}
+	return key[start+1 : start+1+end]
+}
+
+// keySlot returns the Redis Cluster slot of the key.
+func keySlot(key string) uint16 {
+	if tag := hashTag(key); tag != "" {
+		key = tag
+	}
+	return crc16(key) % 16384
+}
+
+// slotTag returns the smallest number which is hashed to the slot.
+func slotTag(slot uint16) string {
+	for i := 0; ; i++ {
+		tag := strconv.Itoa(i)
+		if crc16(tag)%16384 == slot {
+			return tag
+		}
+	}
+}
+
+// crc16 is the CRC-16/XMODEM checksum used by Redis Cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func (m *RedisLock) logError(ctx context.Context, msg, key string, err error) {
+	if m.logger != nil {
+		m.logger.Errorf(msg+" %v: %v", key, err)
+	}
+	if m.slogLogger != nil {
+		m.slogLogger.ErrorContext(ctx, msg, dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+	}
+}
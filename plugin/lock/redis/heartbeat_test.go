@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisLock_TakeOver(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mr, client := newMiniredisClient(t)
+	mr.SetTime(now)
+	lock := NewRedisLock(client)
+
+	if ok, _, held := lock.TakeOver(ctx, time.Minute, "job", "host2", 50*time.Millisecond); ok || held {
+		t.Fatalf("TakeOver() of a free key = %v, %v, want false, false", ok, held)
+	}
+
+	_, v := lock.Lock(ctx, time.Minute, "job", "host1")
+	// the first watcher starts the heartbeat of a holder which has not heartbeated yet
+	if ok, _, held := lock.TakeOver(ctx, time.Minute, "job", "host2", 50*time.Millisecond); ok || !held {
+		t.Fatalf("TakeOver() of a fresh key = %v, %v, want false, true", ok, held)
+	}
+
+	now = now.Add(100 * time.Millisecond)
+	mr.SetTime(now)
+	if !lock.Heartbeat(ctx, time.Minute, "job", "host1", v) {
+		t.Fatal("Heartbeat() of the holder = false, want true")
+	}
+	if ok, _, held := lock.TakeOver(ctx, time.Minute, "job", "host2", 50*time.Millisecond); ok || !held {
+		t.Fatalf("TakeOver() of a heartbeating key = %v, %v, want false, true", ok, held)
+	}
+
+	now = now.Add(100 * time.Millisecond)
+	mr.SetTime(now)
+	if ok, _, held := lock.TakeOver(ctx, time.Minute, "job", "host2", 50*time.Millisecond); !ok || !held {
+		t.Fatalf("TakeOver() of a stale key = %v, %v, want true, true", ok, held)
+	}
+	if got, _ := mr.Get("job"); got != "host2" {
+		t.Errorf("value of job = %q, want %q", got, "host2")
+	}
+	if lock.Heartbeat(ctx, time.Minute, "job", "host1", v) {
+		t.Error("Heartbeat() of the former holder = true, want false")
+	}
+
+	lock.Unlock(ctx, time.Minute, "job", "host1", v)
+	if !mr.Exists("job") || !mr.Exists("{job}:heartbeat") {
+		t.Error("keys of the new holder are deleted by Unlock() of the former one")
+	}
+
+	lock.Unlock(ctx, time.Minute, "job", "host2", nil)
+	if mr.Exists("job") || mr.Exists("{job}:heartbeat") {
+		t.Error("keys exist after Unlock()")
+	}
+}
+
+func Test_keySlot(t *testing.T) {
+	// the slots reported by CLUSTER KEYSLOT
+	tests := []struct {
+		key  string
+		want uint16
+	}{
+		{key: "foo", want: 12182},
+		{key: "somekey", want: 11058},
+		{key: "{foo}bar", want: 12182},
+		{key: "a{foo}b{bar}", want: 12182},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := keySlot(tt.key); got != tt.want {
+				t.Errorf("keySlot(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisLock_heartbeatKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		hashTag string
+		key     string
+	}{
+		{name: "plain", key: "job"},
+		{name: "hash tag option", hashTag: "app", key: "job"},
+		{name: "own hash tag", key: "{app}job"},
+		{name: "closing brace only", key: "a}b"},
+		{name: "opening brace only", key: "a{b"},
+		{name: "empty hash tag", key: "a{}b"},
+		{name: "leading empty hash tag", key: "{}x"},
+		{name: "closing brace before hash tag", key: "a}b{c}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lock := NewRedisLock(nil, WithHashTag(tt.hashTag))
+			redisKey, heartbeatKey := lock.redisKey(tt.key), lock.heartbeatKey(tt.key)
+			if heartbeatKey == redisKey {
+				t.Fatalf("heartbeatKey(%q) = the lock key %q", tt.key, redisKey)
+			}
+			if got, want := keySlot(heartbeatKey), keySlot(redisKey); got != want {
+				t.Errorf("slot of heartbeatKey(%q) = %q is %v, want %v of %q", tt.key, heartbeatKey, got, want, redisKey)
+			}
+		})
+	}
+}
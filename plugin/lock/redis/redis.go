@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	redisV9 "github.com/redis/go-redis/v9"
@@ -9,44 +10,55 @@ import (
 	"github.com/nkonev/dcron"
 )
 
+// unlockScript deletes the key and its heartbeat only if the key is still held by the owner.
+var unlockScript = redisV9.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1], KEYS[2])
+else
+	return 0
+end`)
+
 type RedisLock struct {
-	client     *redisV9.Client
-	logger     dcron.Logger
-	slogLogger dcron.SlogLogger
+	client      redisV9.UniversalClient
+	hashTag     string
+	defaultTTL  time.Duration
+	driftFactor float64
+	logger      dcron.Logger
+	slogLogger  dcron.SlogLogger
 }
 
+// LockTTL is the job setting of the lock TTL.
+var LockTTL = dcron.NewSettingKey[time.Duration]("redis.LockTTL")
+
 func WithLockTTL(duration time.Duration) dcron.JobOption {
-	return dcron.WithJobSettings(duration)
+	return dcron.WithSetting(LockTTL, duration)
 }
 
-func WithLock(redisClient *redisV9.Client, options ...RedisLockOption) dcron.CronOption {
+// WithLock uses RedisLock with any kind of client: *redisV9.Client, *redisV9.ClusterClient,
+// failover clients of Sentinel or *redisV9.Ring.
+func WithLock(redisClient redisV9.UniversalClient, options ...RedisLockOption) dcron.CronOption {
 	return dcron.WithLock(NewRedisLock(redisClient, options...))
 }
 
 func (m *RedisLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
-	duration, ok := jobSettings.(time.Duration)
-	if !ok {
-		if m.logger != nil {
-			m.logger.Errorf("unable to cast to time.Duration %v", key)
-		}
-		if m.slogLogger != nil {
-			m.slogLogger.ErrorContext(ctx, "unable to cast to time.Duration", dcron.SlogKeyTaskName, key)
-		}
-
-		return false, nil
-	}
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
 
-	if duration == 0 {
+// TryLock implements dcron.FallibleLock.
+func (m *RedisLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	duration, err := m.ttl(ctx, jobSettings)
+	if err != nil {
 		if m.logger != nil {
-			m.logger.Errorf("bad zero expiration %v", key)
+			m.logger.Errorf("unable to get lock ttl %v: %v", key, err)
 		}
 		if m.slogLogger != nil {
-			m.slogLogger.ErrorContext(ctx, "bad zero expiration", dcron.SlogKeyTaskName, key)
+			m.slogLogger.ErrorContext(ctx, "unable to get lock ttl", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
 		}
-		return false, nil
+		return false, nil, err
 	}
 
-	locked, err := m.client.SetNX(ctx, key, value, duration).Result()
+	locked, err := m.client.SetNX(ctx, m.redisKey(key), value, duration).Result()
 	if err != nil {
 		if m.logger != nil {
 			m.logger.Errorf("unable to take redis lock %v: %v", key, err)
@@ -54,18 +66,73 @@ func (m *RedisLock) Lock(ctx context.Context, jobSettings any, key, value string
 		if m.slogLogger != nil {
 			m.slogLogger.ErrorContext(ctx, "unable to take redis lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
 		}
-		return false, nil
+		return false, nil, err
 	}
 
-	return locked, nil
+	return locked, nil, nil
 }
 
 func (m *RedisLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
-	m.client.Del(ctx, key)
+	err := unlockScript.Run(context.WithoutCancel(ctx), m.client, []string{m.redisKey(key), m.heartbeatKey(key)}, value).Err()
+	if err != nil {
+		m.logError(ctx, "unable to release redis lock", key, err)
+	}
+}
+
+// ValidateJob implements dcron.LockValidator.
+func (m *RedisLock) ValidateJob(job dcron.JobMeta, jobSettings any) error {
+	_, _, err := settingsTTL(job, jobSettings)
+	return err
+}
+
+// ttl returns the lock TTL set by WithLockTTL, or the default one set by WithDefaultLockTTL,
+// or the rest of the schedule interval, so the lock of a crashed holder expires by the next planned run.
+func (m *RedisLock) ttl(ctx context.Context, jobSettings any) (time.Duration, error) {
+	task, _ := dcron.TaskFromContext(ctx)
+	if duration, ok, err := settingsTTL(task.Job, jobSettings); ok || err != nil {
+		return duration, err
+	}
+	if m.defaultTTL > 0 {
+		return m.defaultTTL, nil
+	}
+
+	// the task context lasts until the next planned run
+	nextAt, ok := ctx.Deadline()
+	if !ok {
+		return 0, fmt.Errorf("%w: no lock ttl and no next run to derive it from", dcron.ErrLockSettings)
+	}
+	duration := time.Until(nextAt)
+	if duration < time.Millisecond {
+		return 0, fmt.Errorf("%w: next run is too close to derive lock ttl: %v", dcron.ErrLockSettings, duration)
+	}
+	return duration, nil
 }
 
-func NewRedisLock(redisClient *redisV9.Client, options ...RedisLockOption) *RedisLock {
-	ret := &RedisLock{client: redisClient}
+// settingsTTL returns the lock TTL set for the job by WithLockTTL or dcron.WithJobSettings.
+func settingsTTL(job dcron.JobMeta, jobSettings any) (time.Duration, bool, error) {
+	duration, ok := dcron.ResolveLockTTL(job, LockTTL, jobSettings, 0)
+	if !ok {
+		return 0, false, nil
+	}
+	if duration < time.Millisecond {
+		return 0, false, fmt.Errorf("%w: lock ttl %v", dcron.ErrLockSettings, duration)
+	}
+	return duration, true, nil
+}
+
+// redisKey returns the key stored in Redis for the job key.
+func (m *RedisLock) redisKey(key string) string {
+	if m.hashTag == "" {
+		return key
+	}
+	return "{" + m.hashTag + "}" + key
+}
+
+func NewRedisLock(redisClient redisV9.UniversalClient, options ...RedisLockOption) *RedisLock {
+	ret := &RedisLock{
+		client:      redisClient,
+		driftFactor: DefaultDriftFactor,
+	}
 
 	for _, option := range options {
 		option(ret)
@@ -76,6 +143,29 @@ func NewRedisLock(redisClient *redisV9.Client, options ...RedisLockOption) *Redi
 
 type RedisLockOption func(rl *RedisLock)
 
+// WithDefaultLockTTL sets the lock TTL of the jobs without WithLockTTL.
+// Without it, such jobs are locked until their next planned run.
+func WithDefaultLockTTL(duration time.Duration) RedisLockOption {
+	return func(rl *RedisLock) {
+		rl.defaultTTL = duration
+	}
+}
+
+// WithHashTag prefixes keys with the {tag} hash tag, so Redis Cluster keeps all the locks in the same slot.
+// Without it, a key is hashed as a whole, or by its own braced part if the job key contains one.
+func WithHashTag(tag string) RedisLockOption {
+	return func(rl *RedisLock) {
+		rl.hashTag = tag
+	}
+}
+
+// WithDriftFactor sets the share of the TTL reserved for clock drift between the nodes, it is used by Redlock only.
+func WithDriftFactor(factor float64) RedisLockOption {
+	return func(rl *RedisLock) {
+		rl.driftFactor = factor
+	}
+}
+
 // WithLog sets the classis logger interface.
 func WithLog(logger dcron.Logger) RedisLockOption {
 	return func(rl *RedisLock) {
@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisV9 "github.com/redis/go-redis/v9"
+
+	"github.com/nkonev/dcron"
+)
+
+func newMiniredisClient(t *testing.T) (*miniredis.Miniredis, redisV9.UniversalClient) {
+	mr := miniredis.RunT(t)
+	client := redisV9.NewUniversalClient(&redisV9.UniversalOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestRedisLock(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		options []RedisLockOption
+		wantKey string
+	}{
+		{
+			name:    "regular",
+			wantKey: "job",
+		},
+		{
+			name:    "hash tag",
+			options: []RedisLockOption{WithHashTag("dcron")},
+			wantKey: "{dcron}job",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newMiniredisClient(t)
+			lock := NewRedisLock(client, tt.options...)
+
+			ok, v := lock.Lock(ctx, time.Minute, "job", "host1")
+			if !ok {
+				t.Fatal("Lock() of a free key = false, want true")
+			}
+			if got, _ := mr.Get(tt.wantKey); got != "host1" {
+				t.Errorf("value of %v = %q, want %q", tt.wantKey, got, "host1")
+			}
+			if ok, _ := lock.Lock(ctx, time.Minute, "job", "host2"); ok {
+				t.Fatal("Lock() of a held key = true, want false")
+			}
+
+			lock.Unlock(ctx, time.Minute, "job", "host2", nil)
+			if !mr.Exists(tt.wantKey) {
+				t.Errorf("%v is deleted by Unlock() of another owner", tt.wantKey)
+			}
+
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			lock.Unlock(cancelled, time.Minute, "job", "host1", v)
+			if mr.Exists(tt.wantKey) {
+				t.Errorf("%v exists after Unlock()", tt.wantKey)
+			}
+		})
+	}
+}
+
+func jobMeta(t *testing.T, options ...dcron.JobOption) dcron.JobMeta {
+	c := dcron.NewCron()
+	if err := c.AddJobs(dcron.NewJob("job", "* * * * * *", nil, options...)); err != nil {
+		t.Fatal(err)
+	}
+	return c.Jobs()[0]
+}
+
+func Test_settingsTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []dcron.JobOption
+		jobSettings any
+		want        time.Duration
+		wantOk      bool
+		wantErr     bool
+	}{
+		{
+			name:    "typed",
+			options: []dcron.JobOption{WithLockTTL(time.Minute), dcron.WithJobSettings("of another plugin")},
+			want:    time.Minute,
+			wantOk:  true,
+		},
+		{
+			name:        "untyped",
+			jobSettings: time.Minute,
+			want:        time.Minute,
+			wantOk:      true,
+		},
+		{
+			name:        "another plugin",
+			jobSettings: "of another plugin",
+		},
+		{
+			name:    "zero",
+			options: []dcron.JobOption{WithLockTTL(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := settingsTTL(jobMeta(t, tt.options...), tt.jobSettings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("settingsTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("settingsTTL() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRedisLock_ttl(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []RedisLockOption
+		deadline time.Duration
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "plugin default",
+			options:  []RedisLockOption{WithDefaultLockTTL(time.Hour)},
+			deadline: time.Second,
+			want:     time.Hour,
+		},
+		{
+			name:     "next run",
+			deadline: 10 * time.Second,
+			want:     10 * time.Second,
+		},
+		{
+			name:    "no next run",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			got, err := NewRedisLock(nil, tt.options...).ttl(ctx, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ttl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// the rest of the interval is a bit less than the timeout
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("ttl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisLock_ValidateJob(t *testing.T) {
+	lock := NewRedisLock(nil)
+	if err := lock.ValidateJob(jobMeta(t), nil); err != nil {
+		t.Errorf("ValidateJob() without settings = %v, want nil", err)
+	}
+	if err := lock.ValidateJob(jobMeta(t, WithLockTTL(time.Minute)), nil); err != nil {
+		t.Errorf("ValidateJob() with ttl = %v, want nil", err)
+	}
+	if err := lock.ValidateJob(jobMeta(t), "of another plugin"); err != nil {
+		t.Errorf("ValidateJob() with settings of another plugin = %v, want nil", err)
+	}
+	if err := lock.ValidateJob(jobMeta(t, WithLockTTL(-time.Minute)), nil); err == nil {
+		t.Error("ValidateJob() with negative ttl = nil, want error")
+	}
+}
@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	redisV9 "github.com/redis/go-redis/v9"
+
+	"github.com/nkonev/dcron"
+)
+
+// DefaultDriftFactor is the share of the TTL reserved for clock drift between the Redlock nodes.
+const DefaultDriftFactor = 0.01
+
+// releaseScript deletes the key only if it still holds the token of the releasing lock.
+var releaseScript = redisV9.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+else
+	return 0
+end`)
+
+// Redlock implements dcron.Lock via the Redlock algorithm:
+// the key is taken on the majority of independent Redis nodes, so a failover of one of them does not lose the lock.
+// The lock is considered taken only if the majority was reached before the TTL minus the clock drift allowance ran out.
+// Slow nodes are bounded by the timeouts of their clients.
+type Redlock struct {
+	clients []redisV9.UniversalClient
+	config  *RedisLock
+}
+
+// WithRedlock uses Redlock over independent clients, one per Redis node.
+func WithRedlock(clients []redisV9.UniversalClient, options ...RedisLockOption) dcron.CronOption {
+	return dcron.WithLock(NewRedlock(clients, options...))
+}
+
+func NewRedlock(clients []redisV9.UniversalClient, options ...RedisLockOption) *Redlock {
+	return &Redlock{
+		clients: clients,
+		config:  NewRedisLock(nil, options...),
+	}
+}
+
+func (m *Redlock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+// The lock is reported as held by another instance only if enough nodes answered so;
+// if the failed nodes could have changed the outcome, the error is returned.
+func (m *Redlock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	c := m.config
+	duration, err := c.ttl(ctx, jobSettings)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Errorf("unable to get lock ttl %v: %v", key, err)
+		}
+		if c.slogLogger != nil {
+			c.slogLogger.ErrorContext(ctx, "unable to get lock ttl", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	token, err := newToken(value)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Errorf("unable to generate redlock token %v: %v", key, err)
+		}
+		if c.slogLogger != nil {
+			c.slogLogger.ErrorContext(ctx, "unable to generate redlock token", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	redisKey := c.redisKey(key)
+	beginAt := time.Now()
+	var mu sync.Mutex
+	var errs []error
+	taken := m.forEachClient(func(client redisV9.UniversalClient) bool {
+		locked, err := client.SetNX(ctx, redisKey, token, duration).Result()
+		if err != nil {
+			if c.logger != nil {
+				c.logger.Errorf("unable to take redis lock %v: %v", key, err)
+			}
+			if c.slogLogger != nil {
+				c.slogLogger.ErrorContext(ctx, "unable to take redis lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return false
+		}
+		return locked
+	})
+
+	// 2ms compensate the granularity of Redis expiration
+	drift := time.Duration(float64(duration)*c.driftFactor) + 2*time.Millisecond
+	validity := duration - time.Since(beginAt) - drift
+	quorum := len(m.clients)/2 + 1
+	if taken >= quorum && validity > 0 {
+		return true, token, nil
+	}
+
+	m.release(ctx, key, token)
+	if taken >= quorum {
+		return false, nil, errors.New("redlock validity time ran out while locking")
+	}
+	if taken+len(errs) >= quorum {
+		return false, nil, errors.Join(errs...)
+	}
+	return false, nil, nil
+}
+
+// ValidateJob implements dcron.LockValidator.
+func (m *Redlock) ValidateJob(job dcron.JobMeta, jobSettings any) error {
+	return m.config.ValidateJob(job, jobSettings)
+}
+
+func (m *Redlock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	token, ok := lockValue.(string)
+	if !ok {
+		return
+	}
+	m.release(context.WithoutCancel(ctx), key, token)
+}
+
+// release deletes the key from every node, including the ones where SetNX failed,
+// because a reply could have been lost after the key was set.
+func (m *Redlock) release(ctx context.Context, key, token string) {
+	c := m.config
+	redisKey := c.redisKey(key)
+	m.forEachClient(func(client redisV9.UniversalClient) bool {
+		if err := releaseScript.Run(ctx, client, []string{redisKey}, token).Err(); err != nil {
+			if c.logger != nil {
+				c.logger.Errorf("unable to release redis lock %v: %v", key, err)
+			}
+			if c.slogLogger != nil {
+				c.slogLogger.ErrorContext(ctx, "unable to release redis lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// forEachClient calls f for all the clients concurrently and returns how many calls succeeded.
+func (m *Redlock) forEachClient(f func(client redisV9.UniversalClient) bool) int {
+	var wg sync.WaitGroup
+	results := make([]bool, len(m.clients))
+	for i, client := range m.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = f(client)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, ok := range results {
+		if ok {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// newToken makes the value unique per Lock call, so only this call can release the key.
+func newToken(value string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return value + ":" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisV9 "github.com/redis/go-redis/v9"
+)
+
+func TestRedlock(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		prepare func(nodes []*miniredis.Miniredis)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "all nodes",
+			prepare: func(nodes []*miniredis.Miniredis) {},
+			want:    true,
+		},
+		{
+			name: "one node is down",
+			prepare: func(nodes []*miniredis.Miniredis) {
+				nodes[0].Close()
+			},
+			want: true,
+		},
+		{
+			name: "majority is down",
+			prepare: func(nodes []*miniredis.Miniredis) {
+				nodes[0].Close()
+				nodes[1].Close()
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "majority is held by another",
+			prepare: func(nodes []*miniredis.Miniredis) {
+				_ = nodes[1].Set("job", "another")
+				_ = nodes[2].Set("job", "another")
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []*miniredis.Miniredis
+			var clients []redisV9.UniversalClient
+			for i := 0; i < 3; i++ {
+				mr, client := newMiniredisClient(t)
+				nodes = append(nodes, mr)
+				clients = append(clients, client)
+			}
+			tt.prepare(nodes)
+			lock := NewRedlock(clients)
+
+			ok, v, err := lock.TryLock(ctx, time.Minute, "job", "host1")
+			if ok != tt.want || (err != nil) != tt.wantErr {
+				t.Fatalf("TryLock() = %v, %v, want %v, error %v", ok, err, tt.want, tt.wantErr)
+			}
+			if !ok {
+				// a failed attempt must not leave its keys behind
+				if got, _ := nodes[0].Get("job"); nodes[0].Exists("job") && got != "another" {
+					t.Errorf("node 0 keeps %q after a failed Lock()", got)
+				}
+				return
+			}
+
+			if ok, _ := lock.Lock(ctx, time.Minute, "job", "host2"); ok {
+				t.Fatal("Lock() of a held key = true, want false")
+			}
+			lock.Unlock(ctx, time.Minute, "job", "host1", v)
+			if ok, _ := lock.Lock(ctx, time.Minute, "job", "host2"); !ok {
+				t.Fatal("Lock() of an unlocked key = false, want true")
+			}
+		})
+	}
+}
+
+func TestRedlock_validity(t *testing.T) {
+	var clients []redisV9.UniversalClient
+	for i := 0; i < 3; i++ {
+		_, client := newMiniredisClient(t)
+		clients = append(clients, client)
+	}
+	// the whole ttl is eaten by the drift allowance
+	lock := NewRedlock(clients, WithDriftFactor(1))
+	if ok, _ := lock.Lock(context.Background(), time.Minute, "job", "host1"); ok {
+		t.Fatal("Lock() without validity time left = true, want false")
+	}
+}
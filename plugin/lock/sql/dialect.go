@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"context"
+	stdSQL "database/sql"
+	"fmt"
+)
+
+// Dialect describes the SQL flavour of the database holding the lock table.
+type Dialect interface {
+	// createTable returns the DDL creating the lock table if it does not exist.
+	createTable(table string) string
+	// acquire inserts the lock row or takes over an expired one, and reports whether the lock was taken.
+	acquire(ctx context.Context, db *stdSQL.DB, table, key, owner string, expiresAt, now int64) (bool, error)
+	// release removes the lock row if it is still held by the owner.
+	release(ctx context.Context, db *stdSQL.DB, table, key, owner string) error
+}
+
+var (
+	// Postgres is the dialect for PostgreSQL 9.5 and later.
+	Postgres Dialect = postgresDialect{}
+	// MySQL is the dialect for MySQL and MariaDB.
+	MySQL Dialect = mysqlDialect{}
+	// SQLite is the dialect for SQLite 3.24 and later.
+	SQLite Dialect = sqliteDialect{}
+)
+
+func createTableDDL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	lock_key VARCHAR(255) NOT NULL PRIMARY KEY,
+	owner VARCHAR(255) NOT NULL,
+	expires_at BIGINT NOT NULL
+)`, table)
+}
+
+func rowsAffected(res stdSQL.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) createTable(table string) string {
+	return createTableDDL(table)
+}
+
+func (postgresDialect) acquire(ctx context.Context, db *stdSQL.DB, table, key, owner string, expiresAt, now int64) (bool, error) {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (lock_key, owner, expires_at) VALUES ($1, $2, $3)
+ON CONFLICT (lock_key) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+WHERE %[1]s.expires_at <= $4`, table)
+	return rowsAffected(db.ExecContext(ctx, query, key, owner, expiresAt, now))
+}
+
+func (postgresDialect) release(ctx context.Context, db *stdSQL.DB, table, key, owner string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE lock_key = $1 AND owner = $2`, table), key, owner)
+	return err
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) createTable(table string) string {
+	return createTableDDL(table)
+}
+
+func (mysqlDialect) acquire(ctx context.Context, db *stdSQL.DB, table, key, owner string, expiresAt, now int64) (bool, error) {
+	// Without CLIENT_FOUND_ROWS MySQL reports 1 for an insert, 2 for an update and 0 when nothing changed.
+	// Columns are assigned left to right, so expires_at is compared before it is overwritten in both branches.
+	query := fmt.Sprintf(`INSERT INTO %s (lock_key, owner, expires_at) VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE
+	owner = IF(expires_at <= ?, VALUES(owner), owner),
+	expires_at = IF(expires_at <= ?, VALUES(expires_at), expires_at)`, table)
+	return rowsAffected(db.ExecContext(ctx, query, key, owner, expiresAt, now, now))
+}
+
+func (mysqlDialect) release(ctx context.Context, db *stdSQL.DB, table, key, owner string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE lock_key = ? AND owner = ?`, table), key, owner)
+	return err
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) createTable(table string) string {
+	return createTableDDL(table)
+}
+
+func (sqliteDialect) acquire(ctx context.Context, db *stdSQL.DB, table, key, owner string, expiresAt, now int64) (bool, error) {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (lock_key, owner, expires_at) VALUES (?1, ?2, ?3)
+ON CONFLICT (lock_key) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+WHERE %[1]s.expires_at <= ?4`, table)
+	return rowsAffected(db.ExecContext(ctx, query, key, owner, expiresAt, now))
+}
+
+func (sqliteDialect) release(ctx context.Context, db *stdSQL.DB, table, key, owner string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE lock_key = ? AND owner = ?`, table), key, owner)
+	return err
+}
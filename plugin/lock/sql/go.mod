@@ -0,0 +1,17 @@
+module github.com/nkonev/dcron/plugin/lock/sql
+
+go 1.23.0
+
+require (
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/nkonev/dcron v1.8.0
+)
+
+require (
+	filippo.io/edwards25519 v1.1.1 // indirect
+	github.com/robfig/cron/v3 v3.0.1 // indirect
+)
+
+replace github.com/nkonev/dcron => ../../..
@@ -0,0 +1,164 @@
+package sql
+
+import (
+	"context"
+	"crypto/rand"
+	stdSQL "database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/nkonev/dcron"
+)
+
+// DefaultTableName is the name of the lock table unless WithTableName is used.
+const DefaultTableName = "dcron_locks"
+
+// SQLLock implements dcron.Lock on top of a table with (lock_key, owner, expires_at) columns.
+// The owner is the instance value followed by a random token, so only the acquisition that took the row can delete it.
+// The expiration is stored as unix milliseconds computed on the instance, so clocks of the instances should be in sync.
+type SQLLock struct {
+	db         *stdSQL.DB
+	dialect    Dialect
+	table      string
+	logger     dcron.Logger
+	slogLogger dcron.SlogLogger
+}
+
+// LockTTL is the job setting of the lock TTL.
+var LockTTL = dcron.NewSettingKey[time.Duration]("sql.LockTTL")
+
+func WithLockTTL(duration time.Duration) dcron.JobOption {
+	return dcron.WithSetting(LockTTL, duration)
+}
+
+func WithLock(db *stdSQL.DB, dialect Dialect, options ...SQLLockOption) dcron.CronOption {
+	return dcron.WithLock(NewSQLLock(db, dialect, options...))
+}
+
+// Migrate creates the lock table if it does not exist.
+func (m *SQLLock) Migrate(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, m.dialect.createTable(m.table))
+	return err
+}
+
+func (m *SQLLock) Lock(ctx context.Context, jobSettings any, key, value string) (bool, any) {
+	locked, lockValue, _ := m.TryLock(ctx, jobSettings, key, value)
+	return locked, lockValue
+}
+
+// TryLock implements dcron.FallibleLock.
+func (m *SQLLock) TryLock(ctx context.Context, jobSettings any, key, value string) (bool, any, error) {
+	task, _ := dcron.TaskFromContext(ctx)
+	duration, ok := dcron.ResolveLockTTL(task.Job, LockTTL, jobSettings, 0)
+	if !ok {
+		if m.logger != nil {
+			m.logger.Errorf("no lock ttl, use WithLockTTL %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "no lock ttl, use WithLockTTL", dcron.SlogKeyTaskName, key)
+		}
+
+		return false, nil, fmt.Errorf("%w: no lock ttl", dcron.ErrLockSettings)
+	}
+
+	if duration == 0 {
+		if m.logger != nil {
+			m.logger.Errorf("bad zero expiration %v", key)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "bad zero expiration", dcron.SlogKeyTaskName, key)
+		}
+		return false, nil, fmt.Errorf("%w: zero expiration", dcron.ErrLockSettings)
+	}
+
+	token, err := newToken(value)
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to generate sql lock token %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to generate sql lock token", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+
+	now := time.Now()
+	locked, err := m.dialect.acquire(ctx, m.db, m.table, key, token, now.Add(duration).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to take sql lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to take sql lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+		return false, nil, err
+	}
+	if !locked {
+		return false, nil, nil
+	}
+
+	return true, token, nil
+}
+
+func (m *SQLLock) Unlock(ctx context.Context, jobSetting any, key, value string, lockValue any) {
+	token, ok := lockValue.(string)
+	if !ok {
+		return
+	}
+	if err := m.dialect.release(context.WithoutCancel(ctx), m.db, m.table, key, token); err != nil {
+		if m.logger != nil {
+			m.logger.Errorf("unable to release sql lock %v: %v", key, err)
+		}
+		if m.slogLogger != nil {
+			m.slogLogger.ErrorContext(ctx, "unable to release sql lock", dcron.SlogKeyTaskName, key, dcron.SlogKeyError, err)
+		}
+	}
+}
+
+// newToken makes the owner unique per Lock call, so a stale Unlock of the same instance does not delete a newer row.
+func newToken(value string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return value + ":" + hex.EncodeToString(b), nil
+}
+
+func NewSQLLock(db *stdSQL.DB, dialect Dialect, options ...SQLLockOption) *SQLLock {
+	ret := &SQLLock{
+		db:      db,
+		dialect: dialect,
+		table:   DefaultTableName,
+	}
+
+	for _, option := range options {
+		option(ret)
+	}
+
+	return ret
+}
+
+type SQLLockOption func(sl *SQLLock)
+
+// WithTableName overrides the name of the lock table.
+// The name is put into the queries as is, so it must be trusted.
+func WithTableName(table string) SQLLockOption {
+	return func(sl *SQLLock) {
+		sl.table = table
+	}
+}
+
+// WithLog sets the classis logger interface.
+func WithLog(logger dcron.Logger) SQLLockOption {
+	return func(sl *SQLLock) {
+		sl.logger = logger
+	}
+}
+
+// WithSLog sets the structured logger interface.
+func WithSLog(logger dcron.SlogLogger) SQLLockOption {
+	return func(sl *SQLLock) {
+		sl.slogLogger = logger
+	}
+}
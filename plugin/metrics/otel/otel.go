@@ -0,0 +1,53 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/nkonev/dcron"
+)
+
+const (
+	AttributeLockKey    = "dcron.lock.key"
+	AttributeLockResult = "dcron.lock.result"
+)
+
+// NewLockObserver returns a dcron.LockObserver for dcron.WithLockObserver,
+// which records the "dcron.lock.acquisitions" counter and the "dcron.lock.duration" histogram in seconds,
+// both with the lock key and the result attributes.
+func NewLockObserver(meter metric.Meter) (dcron.LockObserver, error) {
+	acquisitions, err := meter.Int64Counter("dcron.lock.acquisitions",
+		metric.WithDescription("Number of lock acquisitions"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram("dcron.lock.duration",
+		metric.WithDescription("Duration of lock acquisitions"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, observation dcron.LockObservation) {
+		attributes := metric.WithAttributes(
+			attribute.String(AttributeLockKey, observation.Key),
+			attribute.String(AttributeLockResult, string(observation.Result)),
+		)
+		acquisitions.Add(ctx, 1, attributes)
+		duration.Record(ctx, observation.Latency.Seconds(), attributes)
+	}, nil
+}
+
+// WithLock wraps the lock into dcron.InstrumentedLock recording the metrics of NewLockObserver.
+func WithLock(meter metric.Meter, lock dcron.Lock, options ...dcron.InstrumentedLockOption) (dcron.CronOption, error) {
+	observer, err := NewLockObserver(meter)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, dcron.WithLockObserver(observer))
+	return dcron.WithLock(dcron.NewInstrumentedLock(lock, options...)), nil
+}
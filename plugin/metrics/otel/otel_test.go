@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/nkonev/dcron"
+)
+
+func TestNewLockObserver(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	meter := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("dcron")
+
+	observer, err := NewLockObserver(meter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := dcron.NewInstrumentedLock(dcron.NewMemoryLock(time.Minute), dcron.WithLockObserver(observer))
+	lock.Lock(ctx, nil, "job", "host1")
+	lock.Lock(ctx, nil, "job", "host2")
+	lock.Lock(ctx, nil, "job", "host3")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	acquisitions, ok := metrics["dcron.lock.acquisitions"].Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("no acquisitions counter in %v", metrics)
+	}
+	counts := map[string]int64{}
+	for _, point := range acquisitions.DataPoints {
+		if key, _ := point.Attributes.Value(AttributeLockKey); key != attribute.StringValue("job") {
+			t.Errorf("lock key attribute = %v", key)
+		}
+		result, _ := point.Attributes.Value(AttributeLockResult)
+		counts[result.AsString()] = point.Value
+	}
+	if counts[string(dcron.LockWon)] != 1 || counts[string(dcron.LockLost)] != 2 {
+		t.Errorf("acquisitions by result = %v", counts)
+	}
+
+	duration, ok := metrics["dcron.lock.duration"].Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("no duration histogram in %v", metrics)
+	}
+	var total uint64
+	for _, point := range duration.DataPoints {
+		total += point.Count
+	}
+	if total != 3 {
+		t.Errorf("duration histogram has %d records, want 3", total)
+	}
+	if unit := metrics["dcron.lock.duration"].Unit; unit != "s" {
+		t.Errorf("duration unit = %q", unit)
+	}
+}
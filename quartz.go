@@ -0,0 +1,389 @@
+package dcron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// QuartzParser parses Quartz-style specs, see WithParser:
+//
+//	second minute hour day-of-month month day-of-week [year]
+//
+// Besides lists, ranges and steps it supports the Quartz extensions:
+//   - ? in day of month or day of week, one of them should be ? or *;
+//   - L, L-3 and LW in day of month for the last day, the third day before it and the last weekday of the month;
+//   - 15W in day of month for the weekday nearest to the 15th within the month;
+//   - 6L in day of week for the last Friday of the month, and L alone for Saturday;
+//   - 6#3 in day of week for the third Friday of the month.
+//
+// Mind that days of week are numbered as in Quartz, from 1 for Sunday to 7 for Saturday,
+// and the names SUN-SAT and JAN-DEC may be used instead of numbers.
+// The CRON_TZ= prefix and the descriptors like @daily or @every 5m are supported as well.
+type QuartzParser struct{}
+
+// quartzSchedule is a cron.Schedule of a Quartz-style spec.
+type quartzSchedule struct {
+	second, minute, hour, month uint64
+	years                       map[int]bool // nil for any year
+	dom                         quartzDayOfMonth
+	dow                         quartzDayOfWeek
+
+	// Location is time.Local to evaluate the schedule in the location of the cron, as in cron.SpecSchedule.
+	Location *time.Location
+}
+
+type quartzDayOfMonth struct {
+	any            bool
+	days           uint64
+	last           bool
+	lastOffset     int
+	lastWeekday    bool
+	nearestWeekday int
+}
+
+type quartzDayOfWeek struct {
+	any  bool
+	days uint64 // bits of time.Weekday
+	last bool   // the last days of the month only
+	nth  int    // the nth day of the month only, if positive
+}
+
+var (
+	quartzMonths = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	quartzDays = map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}
+)
+
+// quartzMaxYears bounds the search of the next run of a spec which never matches, such as the 30th of February.
+const quartzMaxYears = 5
+
+// Parse implements cron.ScheduleParser.
+func (p QuartzParser) Parse(spec string) (cron.Schedule, error) {
+	loc := time.Local
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.Index(spec, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("no schedule after time zone prefix: %v", spec)
+		}
+		var err error
+		name := spec[strings.Index(spec, "=")+1 : i]
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("provided bad location %s: %v", name, err)
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+	if strings.HasPrefix(spec, "@") {
+		schedule, err := defaultParser.Parse(spec)
+		if s, ok := schedule.(*cron.SpecSchedule); ok {
+			s.Location = loc
+		}
+		return schedule, err
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("expected 6 or 7 fields, found %d: %v", len(fields), spec)
+	}
+
+	s := &quartzSchedule{Location: loc}
+	var err error
+	if s.second, err = parseQuartzField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("second: %w", err)
+	}
+	if s.minute, err = parseQuartzField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseQuartzField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseQuartzDayOfMonth(fields[3]); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseQuartzField(fields[4], 1, 12, quartzMonths); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseQuartzDayOfWeek(fields[5]); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if len(fields) == 7 && fields[6] != "*" {
+		years, err := parseQuartzValues(fields[6], 1970, 2099, nil)
+		if err != nil {
+			return nil, fmt.Errorf("year: %w", err)
+		}
+		s.years = map[int]bool{}
+		for _, y := range years {
+			s.years[y] = true
+		}
+	}
+	if !s.dom.any && !s.dow.any {
+		return nil, fmt.Errorf("one of day of month and day of week should be ? or *: %v", spec)
+	}
+	return s, nil
+}
+
+func parseQuartzDayOfMonth(field string) (quartzDayOfMonth, error) {
+	switch {
+	case field == "?" || field == "*":
+		return quartzDayOfMonth{any: true}, nil
+	case field == "L":
+		return quartzDayOfMonth{last: true}, nil
+	case field == "LW":
+		return quartzDayOfMonth{lastWeekday: true}, nil
+	case strings.HasPrefix(field, "L-"):
+		offset, err := parseQuartzNumber(field[2:], 0, 30, nil)
+		if err != nil {
+			return quartzDayOfMonth{}, err
+		}
+		return quartzDayOfMonth{last: true, lastOffset: offset}, nil
+	case strings.HasSuffix(field, "W"):
+		day, err := parseQuartzNumber(field[:len(field)-1], 1, 31, nil)
+		if err != nil {
+			return quartzDayOfMonth{}, err
+		}
+		return quartzDayOfMonth{nearestWeekday: day}, nil
+	}
+	days, err := parseQuartzField(field, 1, 31, nil)
+	return quartzDayOfMonth{days: days}, err
+}
+
+func parseQuartzDayOfWeek(field string) (quartzDayOfWeek, error) {
+	switch {
+	case field == "?" || field == "*":
+		return quartzDayOfWeek{any: true}, nil
+	case field == "L":
+		return quartzDayOfWeek{days: 1 << time.Saturday}, nil
+	case strings.HasSuffix(field, "L"):
+		day, err := parseQuartzNumber(field[:len(field)-1], 1, 7, quartzDays)
+		if err != nil {
+			return quartzDayOfWeek{}, err
+		}
+		return quartzDayOfWeek{days: 1 << uint(day-1), last: true}, nil
+	case strings.Contains(field, "#"):
+		i := strings.Index(field, "#")
+		day, err := parseQuartzNumber(field[:i], 1, 7, quartzDays)
+		if err != nil {
+			return quartzDayOfWeek{}, err
+		}
+		nth, err := parseQuartzNumber(field[i+1:], 1, 5, nil)
+		if err != nil {
+			return quartzDayOfWeek{}, err
+		}
+		return quartzDayOfWeek{days: 1 << uint(day-1), nth: nth}, nil
+	}
+	bits, err := parseQuartzField(field, 1, 7, quartzDays)
+	// Quartz numbers days from 1 for Sunday, time.Weekday from 0
+	return quartzDayOfWeek{days: bits >> 1}, err
+}
+
+// parseQuartzField parses a comma separated list of numbers, ranges and steps into bits.
+func parseQuartzField(field string, min, max int, names map[string]int) (uint64, error) {
+	values, err := parseQuartzValues(field, min, max, names)
+	var bits uint64
+	for _, v := range values {
+		bits |= 1 << uint(v)
+	}
+	return bits, err
+}
+
+// parseQuartzValues parses a comma separated list of numbers, ranges and steps.
+func parseQuartzValues(field string, min, max int, names map[string]int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(field, ",") {
+		rangeAndStep := strings.SplitN(item, "/", 2)
+		from, to := min, max
+		switch r := rangeAndStep[0]; {
+		case r == "*":
+		case strings.Contains(r, "-"):
+			i := strings.Index(r, "-")
+			var err error
+			if from, err = parseQuartzNumber(r[:i], min, max, names); err != nil {
+				return nil, err
+			}
+			if to, err = parseQuartzNumber(r[i+1:], min, max, names); err != nil {
+				return nil, err
+			}
+			if from > to {
+				return nil, fmt.Errorf("beginning of range %d beyond its end %d", from, to)
+			}
+		default:
+			var err error
+			if from, err = parseQuartzNumber(r, min, max, names); err != nil {
+				return nil, err
+			}
+			to = from
+			if len(rangeAndStep) == 2 {
+				to = max
+			}
+		}
+		step := 1
+		if len(rangeAndStep) == 2 {
+			var err error
+			if step, err = parseQuartzNumber(rangeAndStep[1], 1, max, nil); err != nil {
+				return nil, err
+			}
+		}
+		for i := from; i <= to; i += step {
+			values = append(values, i)
+		}
+	}
+	return values, nil
+}
+
+func parseQuartzNumber(s string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %q", s)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d out of range [%d, %d]", n, min, max)
+	}
+	return n, nil
+}
+
+// Next implements cron.Schedule.
+// Like cron.SpecSchedule, it skips the times which do not exist because of daylight saving time.
+func (s *quartzSchedule) Next(t time.Time) time.Time {
+	origLocation := t.Location()
+	loc := s.Location
+	if loc == time.Local {
+		loc = origLocation
+	}
+	t = t.In(loc).Add(time.Second - time.Duration(t.Nanosecond()))
+
+	y, m, d := t.Date()
+	for y <= t.Year()+quartzMaxYears {
+		if s.years != nil && !s.years[y] {
+			y, m, d = y+1, time.January, 1
+			continue
+		}
+		if s.month&(1<<uint(m)) == 0 {
+			y, m, d = nextMonth(y, m)
+			continue
+		}
+		lastDay := time.Date(y, m+1, 0, 12, 0, 0, 0, loc).Day()
+		if d > lastDay {
+			y, m, d = nextMonth(y, m)
+			continue
+		}
+		if s.matchDay(y, m, d, lastDay, loc) {
+			if next, ok := s.timeOfDay(y, m, d, t, loc); ok {
+				return next.In(origLocation)
+			}
+		}
+		d++
+	}
+	return time.Time{}
+}
+
+func nextMonth(y int, m time.Month) (int, time.Month, int) {
+	if m == time.December {
+		return y + 1, time.January, 1
+	}
+	return y, m + 1, 1
+}
+
+func (s *quartzSchedule) matchDay(y int, m time.Month, d, lastDay int, loc *time.Location) bool {
+	weekday := func(day int) time.Weekday {
+		return time.Date(y, m, day, 12, 0, 0, 0, loc).Weekday()
+	}
+
+	dom := s.dom
+	switch {
+	case dom.any:
+	case dom.last:
+		if d != lastDay-dom.lastOffset {
+			return false
+		}
+	case dom.lastWeekday:
+		day := lastDay
+		for weekday(day) == time.Saturday || weekday(day) == time.Sunday {
+			day--
+		}
+		if d != day {
+			return false
+		}
+	case dom.nearestWeekday > 0:
+		day := dom.nearestWeekday
+		if day > lastDay {
+			return false
+		}
+		switch weekday(day) {
+		case time.Saturday:
+			if day == 1 {
+				day += 2
+			} else {
+				day--
+			}
+		case time.Sunday:
+			if day == lastDay {
+				day -= 2
+			} else {
+				day++
+			}
+		}
+		if d != day {
+			return false
+		}
+	default:
+		if dom.days&(1<<uint(d)) == 0 {
+			return false
+		}
+	}
+
+	dow := s.dow
+	if dow.any {
+		return true
+	}
+	if dow.days&(1<<uint(weekday(d))) == 0 {
+		return false
+	}
+	if dow.last && d+7 <= lastDay {
+		return false
+	}
+	if dow.nth > 0 && (d-1)/7+1 != dow.nth {
+		return false
+	}
+	return true
+}
+
+// timeOfDay returns the first time of the day matching the schedule not before t.
+func (s *quartzSchedule) timeOfDay(y int, m time.Month, d int, t time.Time, loc *time.Location) (time.Time, bool) {
+	ty, tm, td := t.Date()
+	sameDay := y == ty && m == tm && d == td
+
+	for h := 0; h < 24; h++ {
+		if s.hour&(1<<uint(h)) == 0 || sameDay && h < t.Hour() {
+			continue
+		}
+		for mi := 0; mi < 60; mi++ {
+			if s.minute&(1<<uint(mi)) == 0 || sameDay && h == t.Hour() && mi < t.Minute() {
+				continue
+			}
+			for sec := 0; sec < 60; sec++ {
+				if s.second&(1<<uint(sec)) == 0 {
+					continue
+				}
+				next := time.Date(y, m, d, h, mi, sec, 0, loc)
+				if next.Hour() != h || next.Minute() != mi {
+					// skipped by daylight saving time
+					continue
+				}
+				if !next.Before(t) {
+					return next, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
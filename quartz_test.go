@@ -0,0 +1,177 @@
+package dcron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestQuartzParser_Parse(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name string
+		spec string
+		want []string
+	}{
+		{
+			name: "plain",
+			spec: "0 0 9 * * ?",
+			want: []string{"2024-01-16T09:00:00Z", "2024-01-17T09:00:00Z"},
+		},
+		{
+			name: "seconds step",
+			spec: "*/20 30 10 ? * *",
+			want: []string{"2024-01-15T10:30:20Z", "2024-01-15T10:30:40Z", "2024-01-16T10:30:00Z"},
+		},
+		{
+			name: "last day of month",
+			spec: "0 0 0 L * ?",
+			want: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z"},
+		},
+		{
+			name: "days before last day of month",
+			spec: "0 0 0 L-2 * ?",
+			want: []string{"2024-01-29T00:00:00Z", "2024-02-27T00:00:00Z"},
+		},
+		{
+			name: "last weekday of month",
+			spec: "0 0 0 LW * ?",
+			want: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-29T00:00:00Z"},
+		},
+		{
+			name: "nearest weekday",
+			spec: "0 0 0 1W * ?",
+			want: []string{"2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z", "2024-04-01T00:00:00Z", "2024-05-01T00:00:00Z", "2024-06-03T00:00:00Z"},
+		},
+		{
+			name: "nearest weekday at month end",
+			spec: "0 0 0 30W * ?",
+			want: []string{"2024-01-30T00:00:00Z", "2024-03-29T00:00:00Z", "2024-04-30T00:00:00Z", "2024-05-30T00:00:00Z", "2024-06-28T00:00:00Z"},
+		},
+		{
+			name: "third friday",
+			spec: "0 0 12 ? * 6#3",
+			want: []string{"2024-01-19T12:00:00Z", "2024-02-16T12:00:00Z"},
+		},
+		{
+			name: "last friday",
+			spec: "0 0 12 ? * FRIL",
+			want: []string{"2024-01-26T12:00:00Z", "2024-02-23T12:00:00Z"},
+		},
+		{
+			name: "weekdays by names",
+			spec: "0 0 8 ? * MON-FRI",
+			want: []string{"2024-01-16T08:00:00Z", "2024-01-17T08:00:00Z", "2024-01-18T08:00:00Z", "2024-01-19T08:00:00Z", "2024-01-22T08:00:00Z"},
+		},
+		{
+			name: "saturday",
+			spec: "0 0 8 ? * L",
+			want: []string{"2024-01-20T08:00:00Z", "2024-01-27T08:00:00Z"},
+		},
+		{
+			name: "years",
+			spec: "0 0 0 1 1 ? 2026,2028",
+			want: []string{"2026-01-01T00:00:00Z", "2028-01-01T00:00:00Z", "0001-01-01T00:00:00Z"},
+		},
+		{
+			name: "time zone",
+			spec: "CRON_TZ=Asia/Tokyo 0 0 9 ? * MON",
+			want: []string{"2024-01-22T00:00:00Z"},
+		},
+		{
+			name: "descriptor",
+			spec: "@daily",
+			want: []string{"2024-01-16T00:00:00Z"},
+		},
+		{
+			name: "never",
+			spec: "0 0 0 30 2 ?",
+			want: []string{"0001-01-01T00:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := QuartzParser{}.Parse(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := from
+			for _, want := range tt.want {
+				next = schedule.Next(next)
+				if got := next.UTC().Format(time.RFC3339); got != want {
+					t.Errorf("Next() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestQuartzParser_Parse_error(t *testing.T) {
+	for _, spec := range []string{
+		"0 0 9 * *",
+		"0 0 9 1 * MON",
+		"0 0 24 * * ?",
+		"0 0 9 ? * 0",
+		"0 0 9 ? * 6#6",
+		"0 0 9 32W * ?",
+		"0 0 9 L-31 * ?",
+		"0 0 9 5-1 * ?",
+		"0 0 9 ? FOO *",
+		"0 0 9 * * ? 1969",
+		"CRON_TZ=Asia/Tokyo",
+	} {
+		if _, err := (QuartzParser{}).Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestWithParser(t *testing.T) {
+	c := NewCron(WithParser(QuartzParser{}))
+	if err := c.AddJobs(NewJob("job", "0 0 12 ? * 6#3", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCron().AddJobs(NewJob("job", "0 0 12 ? * 6#3", nil)); err == nil {
+		t.Error("the default parser accepted a Quartz spec")
+	}
+}
+
+// dailyParser parses any spec as "daily at 09:00" in the location of the times passed to Next.
+type dailyParser struct{}
+
+func (dailyParser) Parse(string) (cron.Schedule, error) {
+	return dailySchedule{}, nil
+}
+
+type dailySchedule struct{}
+
+func (dailySchedule) Next(t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), 9, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func TestWithParser_jobLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	c := NewCron(WithLocation(time.UTC), WithParser(dailyParser{}))
+	if err := c.AddJobs(NewJob("job", "daily", nil, WithJobLocation(tokyo))); err != nil {
+		t.Fatal(err)
+	}
+	j := c.jobs[0]
+	if j.location != tokyo {
+		t.Fatalf("location = %v, want %v", j.location, tokyo)
+	}
+
+	next := c.cron.Entry(j.entryID).Schedule.Next(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	if got, want := next.In(tokyo).Format(time.RFC3339), "2024-01-16T09:00:00+09:00"; got != want {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package dcron
+
+import (
+	"context"
+	"time"
+)
+
+// SettingKey identifies a typed setting of a job.
+// Every plugin declares its own keys, so several plugins can keep their settings in the same job.
+// Keys are compared by identity, so two keys with the same name are still different keys.
+type SettingKey[T any] struct {
+	name string
+}
+
+// NewSettingKey returns a new key, the name is used for descriptive purposes only.
+func NewSettingKey[T any](name string) *SettingKey[T] {
+	return &SettingKey[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *SettingKey[T]) String() string {
+	return k.name
+}
+
+// Get returns the setting of the job, or false if it is not set.
+func (k *SettingKey[T]) Get(job JobMeta) (T, bool) {
+	var zero T
+	if job == nil {
+		return zero, false
+	}
+	value, ok := job.Setting(k)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := value.(T)
+	return typed, ok
+}
+
+// FromContext returns the setting of the job of the Task in the context,
+// it is useful inner the Lock implementations.
+func (k *SettingKey[T]) FromContext(ctx context.Context) (T, bool) {
+	task, ok := TaskFromContext(ctx)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return k.Get(task.Job)
+}
+
+// ResolveLockTTL returns the lock TTL of the job for a Lock implementation:
+// the typed setting of the key, or the time.Duration set by WithJobSettings before the typed settings existed,
+// or the default one if it is not zero. It returns false if none of them is set.
+func ResolveLockTTL(job JobMeta, key *SettingKey[time.Duration], jobSetting any, defaultTTL time.Duration) (time.Duration, bool) {
+	if duration, ok := key.Get(job); ok {
+		return duration, true
+	}
+	if duration, ok := jobSetting.(time.Duration); ok {
+		return duration, true
+	}
+	return defaultTTL, defaultTTL != 0
+}
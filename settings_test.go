@@ -0,0 +1,76 @@
+package dcron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSettingKey(t *testing.T) {
+	ttlKey := NewSettingKey[time.Duration]("ttl")
+	tableKey := NewSettingKey[string]("table")
+	sameNameKey := NewSettingKey[time.Duration]("ttl")
+
+	j := &innerJob{}
+	for _, option := range []JobOption{
+		WithSetting(ttlKey, time.Minute),
+		WithSetting(tableKey, "locks"),
+		WithJobSettings("untyped"),
+	} {
+		option(j)
+	}
+
+	if got, ok := ttlKey.Get(j); !ok || got != time.Minute {
+		t.Errorf("ttlKey.Get() = %v, %v, want %v, true", got, ok, time.Minute)
+	}
+	if got, ok := tableKey.Get(j); !ok || got != "locks" {
+		t.Errorf("tableKey.Get() = %v, %v, want %v, true", got, ok, "locks")
+	}
+	if got, ok := sameNameKey.Get(j); ok {
+		t.Errorf("sameNameKey.Get() = %v, %v, want not set", got, ok)
+	}
+	if got, ok := ttlKey.Get(nil); ok {
+		t.Errorf("ttlKey.Get(nil) = %v, %v, want not set", got, ok)
+	}
+	if j.settings != "untyped" {
+		t.Errorf("settings = %v, want untyped", j.settings)
+	}
+
+	ctx := context.WithValue(context.Background(), keyContextTask, Task{Job: j})
+	if got, ok := ttlKey.FromContext(ctx); !ok || got != time.Minute {
+		t.Errorf("ttlKey.FromContext() = %v, %v, want %v, true", got, ok, time.Minute)
+	}
+	if got, ok := ttlKey.FromContext(context.Background()); ok {
+		t.Errorf("ttlKey.FromContext() without task = %v, %v, want not set", got, ok)
+	}
+}
+
+func TestResolveLockTTL(t *testing.T) {
+	ttlKey := NewSettingKey[time.Duration]("ttl")
+
+	typed := &innerJob{}
+	WithSetting(ttlKey, time.Minute)(typed)
+
+	tests := []struct {
+		name       string
+		job        JobMeta
+		jobSetting any
+		defaultTTL time.Duration
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "typed", job: typed, jobSetting: time.Hour, defaultTTL: time.Second, want: time.Minute, wantOK: true},
+		{name: "legacy", job: &innerJob{}, jobSetting: time.Hour, defaultTTL: time.Second, want: time.Hour, wantOK: true},
+		{name: "default", job: &innerJob{}, jobSetting: "other", defaultTTL: time.Second, want: time.Second, wantOK: true},
+		{name: "no job", jobSetting: time.Hour, want: time.Hour, wantOK: true},
+		{name: "none", job: &innerJob{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveLockTTL(tt.job, ttlKey, tt.jobSetting, tt.defaultTTL)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ResolveLockTTL() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
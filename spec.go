@@ -0,0 +1,140 @@
+package dcron
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/robfig/cron/v3"
+)
+
+// SpecError is an error of a spec, with the position of the wrong field if it is known.
+type SpecError struct {
+	Spec   string
+	Field  string // the name of the wrong field, like "hour", or empty if the spec is wrong as a whole
+	Offset int    // the byte offset of the wrong field or of the wrong part of the spec
+	Err    error
+}
+
+// Error implements error.
+func (e *SpecError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("spec %q at %d: %v", e.Spec, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("spec %q: %s at %d: %v", e.Spec, e.Field, e.Offset, e.Err)
+}
+
+// Unwrap returns the error of the parser.
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+var errNoScheduleAfterTimeZone = errors.New("no schedule after time zone prefix")
+
+var specFieldNames = []string{"second", "minute", "hour", "day of month", "month", "day of week"}
+
+// ParseSpec parses the spec as NewCron does, with the seconds field, and evaluates it in the time zone,
+// unless the spec has a CRON_TZ= prefix. A nil location is the local time zone.
+// The error is a *SpecError with the position of the wrong field.
+func ParseSpec(spec string, loc *time.Location) (cron.Schedule, error) {
+	if (strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=")) && !strings.Contains(spec, " ") {
+		// the parser panics on it
+		return nil, &SpecError{Spec: spec, Field: "time zone", Err: errNoScheduleAfterTimeZone}
+	}
+	schedule, err := defaultParser.Parse(spec)
+	if err != nil {
+		return nil, locateSpecError(spec, err)
+	}
+	if loc == nil {
+		return schedule, nil
+	}
+	return locatedSchedule{schedule: schedule, location: loc}, nil
+}
+
+// Preview parses the spec by the parser of the cron, and returns its n runs after from in the time zone of the cron.
+func (c *Cron) Preview(spec string, from time.Time, n int) ([]time.Time, error) {
+	schedule, location, err := c.parseSpec(spec, nil, c.anchor)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		location = c.location
+	}
+	if location == nil {
+		location = time.Local
+	}
+	return nextRuns(schedule, from.In(location), location, n), nil
+}
+
+// nextRuns returns up to n runs of the schedule after from, stopping at a zero time, in the location.
+func nextRuns(schedule cron.Schedule, from time.Time, location *time.Location, n int) []time.Time {
+	var ret []time.Time
+	for t := from; len(ret) < n; {
+		t = schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		ret = append(ret, t.In(location))
+	}
+	return ret
+}
+
+// locateSpecError finds the field of the spec the default parser has failed on,
+// by parsing every field alone with the others being *.
+func locateSpecError(spec string, err error) *SpecError {
+	specErr := &SpecError{Spec: spec, Err: err}
+
+	rest, base := spec, 0
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.Index(spec, " ")
+		if i < 0 {
+			specErr.Field = "time zone"
+			return specErr
+		}
+		if _, tzErr := time.LoadLocation(spec[strings.Index(spec, "=")+1 : i]); tzErr != nil {
+			specErr.Field = "time zone"
+			return specErr
+		}
+		rest, base = spec[i:], i
+	}
+
+	var fields []string
+	var offsets []int
+	start := -1
+	for i, r := range rest + " " {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			fields = append(fields, rest[start:i])
+			offsets = append(offsets, base+start)
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+
+	switch {
+	case len(fields) > 0 && strings.HasPrefix(fields[0], "@"):
+		specErr.Field = "descriptor"
+		specErr.Offset = offsets[0]
+		return specErr
+	case len(fields) > len(specFieldNames):
+		specErr.Offset = offsets[len(specFieldNames)]
+		return specErr
+	case len(fields) < len(specFieldNames):
+		specErr.Offset = len(spec)
+		return specErr
+	}
+
+	for i, field := range fields {
+		probe := []string{"*", "*", "*", "*", "*", "*"}
+		probe[i] = field
+		if _, fieldErr := defaultParser.Parse(strings.Join(probe, " ")); fieldErr != nil {
+			specErr.Field = specFieldNames[i]
+			specErr.Offset = offsets[i]
+			return specErr
+		}
+	}
+	return specErr
+}
@@ -0,0 +1,104 @@
+package dcron
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	schedule, err := ParseSpec("0 0 9 * * *", tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := schedule.Next(time.Date(2023, 12, 31, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 1, 9, 0, 0, 0, tokyo); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSpec_error(t *testing.T) {
+	tests := []struct {
+		spec       string
+		wantField  string
+		wantOffset int
+	}{
+		{spec: "0 0 25 * * *", wantField: "hour", wantOffset: 4},
+		{spec: "0  0 9 * * MON-FOO", wantField: "day of week", wantOffset: 11},
+		{spec: "*/0 * * * * *", wantField: "second", wantOffset: 0},
+		{spec: "CRON_TZ=Asia/Tokyo 0 0 9 32 * *", wantField: "day of month", wantOffset: 25},
+		{spec: "CRON_TZ=Mars/Olympus 0 0 9 * * *", wantField: "time zone", wantOffset: 0},
+		{spec: "CRON_TZ=UTC", wantField: "time zone", wantOffset: 0},
+		{spec: "TZ=Europe/Paris", wantField: "time zone", wantOffset: 0},
+		{spec: "0 0 9 * *", wantOffset: 9},
+		{spec: "0 0 9 * * * 2024", wantOffset: 12},
+		{spec: "@sometimes", wantField: "descriptor", wantOffset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseSpec(tt.spec, nil)
+			var specErr *SpecError
+			if !errors.As(err, &specErr) {
+				t.Fatalf("ParseSpec() error = %v, want a *SpecError", err)
+			}
+			if specErr.Field != tt.wantField || specErr.Offset != tt.wantOffset {
+				t.Errorf("ParseSpec() error at %q %d, want %q %d: %v", specErr.Field, specErr.Offset, tt.wantField, tt.wantOffset, err)
+			}
+		})
+	}
+}
+
+func TestCron_Preview(t *testing.T) {
+	from := time.Date(2024, 1, 12, 10, 0, 0, 0, time.UTC) // Friday
+
+	got, err := NewCron(WithLocation(time.UTC)).Preview("0 0 9 * * MON-FRI", from, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview() = %v, want %v", got, want)
+	}
+
+	got, err = NewCron(WithLocation(time.UTC), WithParser(QuartzParser{})).Preview("0 0 12 ? * 6#3", from, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{time.Date(2024, 1, 19, 12, 0, 0, 0, time.UTC)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview() = %v, want %v", got, want)
+	}
+
+	if _, err := NewCron().Preview("0 0 25 * * *", from, 1); err == nil {
+		t.Error("Preview() of a bad spec succeeded")
+	}
+}
+
+func Test_innerJob_NextRuns(t *testing.T) {
+	c := NewCron()
+	if err := c.AddJobs(NewJob("job", "0 0 * * * *", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := c.Jobs()[0].NextRuns(3)
+	if len(runs) != 3 {
+		t.Fatalf("NextRuns() = %v", runs)
+	}
+	for i, run := range runs {
+		if run.Minute() != 0 || run.Second() != 0 || !run.After(time.Now()) {
+			t.Errorf("run %v is not at the next hours", run)
+		}
+		if i > 0 && run.Sub(runs[i-1]) != time.Hour {
+			t.Errorf("runs %v and %v are not an hour apart", runs[i-1], run)
+		}
+	}
+}
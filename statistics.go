@@ -2,11 +2,12 @@ package dcron
 
 // Statistics records statistics info for a cron or a job.
 type Statistics struct {
-	TotalTask   int64 // Total count of tasks processed
-	PassedTask  int64 // Number of tasks successfully executed
-	FailedTask  int64 // Number of tasks that failed during execution due to errors
-	SkippedTask int64 // Number of tasks skipped due to BeforeFunc returning true
-	MissedTask  int64 // Number of tasks executed by other instances
+	TotalTask    int64 // Total count of tasks processed
+	PassedTask   int64 // Number of tasks successfully executed
+	FailedTask   int64 // Number of tasks that failed during execution due to errors
+	SkippedTask  int64 // Number of tasks skipped by BeforeFunc, a Calendar, the active window or a cancelled jitter wait
+	MissedTask   int64 // Number of tasks executed by other instances
+	FailoverTask int64 // Number of tasks taken over from crashed instances
 
 	TotalRun   int64 // Total count of execution runs
 	PassedRun  int64 // Number of successfully executed runs
@@ -21,6 +22,7 @@ func (s Statistics) Add(delta Statistics) Statistics {
 	s.FailedTask += delta.FailedTask
 	s.SkippedTask += delta.SkippedTask
 	s.MissedTask += delta.MissedTask
+	s.FailoverTask += delta.FailoverTask
 	s.TotalRun += delta.TotalRun
 	s.PassedRun += delta.PassedRun
 	s.FailedRun += delta.FailedRun
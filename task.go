@@ -13,16 +13,19 @@ const (
 
 // Task is an execute of a job.
 type Task struct {
-	Key        string
-	Cron       CronMeta
-	Job        JobMeta
-	PlanAt     time.Time
-	BeginAt    *time.Time
-	EndAt      *time.Time
-	Return     error
-	Skipped    bool
-	Missed     bool
-	TriedTimes int
+	Key          string
+	Cron         CronMeta
+	Job          JobMeta
+	PlanAt       time.Time
+	BeginAt      *time.Time
+	EndAt        *time.Time
+	Return       error
+	Skipped      bool   // the task has been skipped by BeforeFunc, a Calendar, the active window or a cancelled jitter wait
+	SkipReason   string // the reason of the Calendar which has excluded the task, of the active window or of a cancelled jitter wait
+	Missed       bool
+	Failover     bool          // the task has been taken over from a crashed instance, see WithFailover
+	JitterOffset time.Duration // the delay of BeginAt after PlanAt set by WithJitter
+	TriedTimes   int
 }
 
 // TaskFromContext extracts a Task from a context,
@@ -0,0 +1,68 @@
+package dcron
+
+import (
+	"errors"
+	"time"
+)
+
+// Window limits when a job fires on its spec, see WithActiveWindow, WithStartAt and WithEndAt.
+// The planned runs outside of it are skipped before locking, and the job unregisters itself after EndAt.
+type Window struct {
+	StartAt time.Time // the first moment of the window, or zero for no start
+	EndAt   time.Time // the moment the window ends at, or zero for no end
+
+	// From and To are the daily part of the window as offsets from midnight, To is exclusive.
+	// If From is after To, the daily part spans midnight, and if they are equal, it is the whole day.
+	From, To time.Duration
+	Weekdays []time.Weekday // the days of the daily part, or nil for any day
+}
+
+// Active reports whether t is within the window, the daily part is evaluated in the time zone of t.
+func (w Window) Active(t time.Time) bool {
+	if !w.StartAt.IsZero() && t.Before(w.StartAt) {
+		return false
+	}
+	if !w.EndAt.IsZero() && !t.Before(w.EndAt) {
+		return false
+	}
+
+	day := t
+	if w.From != 0 || w.To != 0 {
+		h, m, s := t.Clock()
+		offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+		switch {
+		case w.From < w.To && (offset < w.From || offset >= w.To):
+			return false
+		case w.From > w.To && offset < w.From && offset >= w.To:
+			return false
+		case w.From > w.To && offset < w.To:
+			// the part after midnight belongs to the window started on the day before
+			day = t.AddDate(0, 0, -1)
+		}
+	}
+
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range w.Weekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// ended reports whether no time from t on is within the window.
+func (w Window) ended(t time.Time) bool {
+	return !w.EndAt.IsZero() && !t.Before(w.EndAt)
+}
+
+func (w Window) validate() error {
+	if w.From < 0 || w.From > 24*time.Hour || w.To < 0 || w.To > 24*time.Hour {
+		return errors.New("active window times of day should be from 0 to 24 hours")
+	}
+	if !w.StartAt.IsZero() && !w.EndAt.IsZero() && !w.StartAt.Before(w.EndAt) {
+		return errors.New("active window should start before its end")
+	}
+	return nil
+}
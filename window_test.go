@@ -0,0 +1,146 @@
+package dcron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWindow_Active(t *testing.T) {
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		name   string
+		window Window
+		t      time.Time
+		want   bool
+	}{
+		{
+			name: "zero",
+			t:    monday,
+			want: true,
+		},
+		{
+			name:   "before start",
+			window: Window{StartAt: monday.Add(time.Hour)},
+			t:      monday,
+			want:   false,
+		},
+		{
+			name:   "at start",
+			window: Window{StartAt: monday},
+			t:      monday,
+			want:   true,
+		},
+		{
+			name:   "at end",
+			window: Window{EndAt: monday},
+			t:      monday,
+			want:   false,
+		},
+		{
+			name:   "within daily window",
+			window: Window{From: 8 * time.Hour, To: 20 * time.Hour, Weekdays: weekdays},
+			t:      monday.Add(8 * time.Hour),
+			want:   true,
+		},
+		{
+			name:   "at the end of daily window",
+			window: Window{From: 8 * time.Hour, To: 20 * time.Hour, Weekdays: weekdays},
+			t:      monday.Add(20 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "daily window on weekend",
+			window: Window{From: 8 * time.Hour, To: 20 * time.Hour, Weekdays: weekdays},
+			t:      monday.Add(-36 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "overnight window after midnight",
+			window: Window{From: 22 * time.Hour, To: 6 * time.Hour, Weekdays: []time.Weekday{time.Monday}},
+			t:      monday.Add(24*time.Hour + 5*time.Hour),
+			want:   true,
+		},
+		{
+			name:   "overnight window after midnight of another day",
+			window: Window{From: 22 * time.Hour, To: 6 * time.Hour, Weekdays: []time.Weekday{time.Monday}},
+			t:      monday.Add(5 * time.Hour),
+			want:   false,
+		},
+		{
+			name:   "overnight window in the afternoon",
+			window: Window{From: 22 * time.Hour, To: 6 * time.Hour},
+			t:      monday.Add(12 * time.Hour),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Active(tt.t); got != tt.want {
+				t.Errorf("Active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithEndAt(t *testing.T) {
+	var mu sync.Mutex
+	var tasks []Task
+	c := NewCron()
+	job := NewJob("job", "* * * * * *", nil,
+		WithStartAt(time.Now().Add(1500*time.Millisecond)),
+		WithEndAt(time.Now().Add(3*time.Second)),
+		WithAfterContextFunc(func(ctx context.Context, task Task) {
+			mu.Lock()
+			tasks = append(tasks, task)
+			mu.Unlock()
+		}))
+	if err := c.AddJobs(job); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Jobs()[0].Window(); got.StartAt.IsZero() || got.EndAt.IsZero() {
+		t.Errorf("Window() = %+v", got)
+	}
+
+	c.Start()
+	time.Sleep(4500 * time.Millisecond)
+	<-c.Stop().Done()
+
+	if len(tasks) == 0 {
+		t.Fatal("the job has never been planned")
+	}
+	var ran int
+	for _, task := range tasks {
+		if task.Skipped {
+			if task.SkipReason != "outside of active window" {
+				t.Errorf("task planned at %v skipped with %q", task.PlanAt, task.SkipReason)
+			}
+			continue
+		}
+		ran++
+	}
+	if ran == 0 {
+		t.Error("the job has never run within its window")
+	}
+	if len(c.Jobs()) != 0 {
+		t.Error("the job is not unregistered after its window")
+	}
+}
+
+func TestCron_AddJobs_window(t *testing.T) {
+	now := time.Now()
+	for _, option := range []JobOption{
+		WithActiveWindow(-time.Hour, time.Hour),
+		WithActiveWindow(time.Hour, 25*time.Hour),
+		WithStartAt(now),
+	} {
+		c := NewCron()
+		err := c.AddJobs(NewJob("job", "* * * * * *", nil, option, WithEndAt(now)))
+		if err == nil {
+			t.Errorf("AddJobs() succeeded")
+		}
+	}
+}